@@ -0,0 +1,230 @@
+// Command langgen regenerates internal/formatter/langdata/*.trigrams from
+// the representative text samples below - run with `go run ./cmd/langgen`
+// after editing a corpus sample. It is a development-time data generator,
+// not part of the shipped binary, so it deliberately keeps its own small
+// copy of the trigram-ranking logic rather than exporting formatter
+// internals just for this.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// profileSize must match formatter.trigramProfileSize; it's duplicated here
+// rather than imported since langgen intentionally doesn't depend on the
+// formatter package.
+const profileSize = 100
+
+// trigramsOf mirrors formatter.trigramsOf: overlapping 3-rune windows,
+// lowercased, with runs of whitespace collapsed to a single space.
+func trigramsOf(text string) []string {
+	text = strings.ToLower(text)
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(" " + text + " ")
+
+	if len(runes) < 3 {
+		return nil
+	}
+
+	grams := make([]string, 0, len(runes)-2)
+	for i := 0; i+3 <= len(runes); i++ {
+		grams = append(grams, string(runes[i:i+3]))
+	}
+	return grams
+}
+
+// rankedProfile mirrors formatter.rankedProfile: trigrams ranked by
+// descending frequency (ties broken by first occurrence), capped to
+// profileSize entries.
+func rankedProfile(text string) []string {
+	counts := make(map[string]int)
+	var order []string
+	for _, g := range trigramsOf(text) {
+		if counts[g] == 0 {
+			order = append(order, g)
+		}
+		counts[g]++
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return counts[order[i]] > counts[order[j]]
+	})
+
+	if len(order) > profileSize {
+		order = order[:profileSize]
+	}
+	return order
+}
+
+// corpora holds several paragraphs of running prose per language, mixing a
+// short narrative passage with more expository sentences so common function
+// words and grammatical endings actually recur enough to rank meaningfully
+// - a single sentence (as the first cut of this table used) repeats nothing
+// and produces an almost-random trigram order.
+var corpora = map[string]string{
+	"en": `It was a dark and stormy night, and the old sailor told his story to
+anyone who would listen. He said that the sea remembers everything it has
+ever swallowed, and that the wind carries the voices of those who never
+came home. The young fishermen laughed at him, but the children believed
+every word. Over the years, the story changed a little each time it was
+told, the way every story does when it passes from one mouth to another.
+Still, the sailor insisted that the heart of it never changed: the sea
+gives, and the sea takes, and it does not explain itself to anyone. When
+the harbor lights came on that evening, he pointed toward the horizon and
+said that somewhere out there, the old ship was still sailing, waiting
+for a crew that would never return. Nobody could say whether any of it
+was true, and nobody really wanted to know.`,
+
+	"es": `Era una noche oscura y tormentosa, y el viejo marinero contaba su
+historia a quien quisiera escucharlo. Decía que el mar recuerda todo lo
+que ha tragado, y que el viento lleva las voces de quienes nunca volvieron
+a casa. Los jóvenes pescadores se reían de él, pero los niños creían cada
+palabra. Con los años, la historia cambiaba un poco cada vez que la
+contaba, como cambia toda historia cuando pasa de una boca a otra. Aun
+así, el marinero insistía en que el corazón de la historia nunca cambiaba:
+el mar da, y el mar quita, y no le explica nada a nadie. Cuando las luces
+del puerto se encendían esa noche, señalaba hacia el horizonte y decía
+que, en algún lugar allá afuera, el viejo barco seguía navegando, esperando
+una tripulación que jamás regresaría.`,
+
+	"fr": `C'était une nuit sombre et orageuse, et le vieux marin racontait son
+histoire à quiconque voulait bien l'écouter. Il disait que la mer se
+souvient de tout ce qu'elle a englouti, et que le vent porte les voix de
+ceux qui ne sont jamais rentrés chez eux. Les jeunes pêcheurs se moquaient
+de lui, mais les enfants croyaient chaque mot. Au fil des années,
+l'histoire changeait un peu chaque fois qu'il la racontait, comme change
+toute histoire quand elle passe d'une bouche à une autre. Pourtant, le
+marin insistait sur le fait que le cœur de l'histoire ne changeait jamais:
+la mer donne, et la mer reprend, et elle ne s'explique à personne. Quand
+les lumières du port s'allumaient ce soir-là, il montrait l'horizon et
+disait que, quelque part là-bas, le vieux navire naviguait encore.`,
+
+	"de": `Es war eine dunkle und stürmische Nacht, und der alte Seemann erzählte
+seine Geschichte jedem, der zuhören wollte. Er sagte, das Meer erinnere
+sich an alles, was es je verschluckt habe, und der Wind trage die Stimmen
+derer, die niemals nach Hause zurückkehrten. Die jungen Fischer lachten
+über ihn, aber die Kinder glaubten jedes Wort. Im Laufe der Jahre änderte
+sich die Geschichte jedes Mal ein wenig, wenn er sie erzählte, so wie sich
+jede Geschichte ändert, wenn sie von einem Mund zum anderen wandert.
+Trotzdem bestand der Seemann darauf, dass sich der Kern der Geschichte nie
+änderte: das Meer gibt, und das Meer nimmt, und es erklärt sich niemandem.
+Als die Lichter des Hafens an diesem Abend angingen, zeigte er zum
+Horizont und sagte, dass irgendwo dort draußen das alte Schiff immer noch
+segelte.`,
+
+	"it": `Era una notte buia e tempestosa, e il vecchio marinaio raccontava la
+sua storia a chiunque volesse ascoltarlo. Diceva che il mare ricorda tutto
+ciò che ha inghiottito, e che il vento porta le voci di coloro che non
+sono mai tornati a casa. I giovani pescatori ridevano di lui, ma i bambini
+credevano a ogni parola. Con il passare degli anni, la storia cambiava un
+poco ogni volta che la raccontava, come cambia ogni storia quando passa da
+una bocca all'altra. Eppure, il marinaio insisteva che il cuore della
+storia non cambiava mai: il mare dà, e il mare prende, e non si spiega a
+nessuno. Quando le luci del porto si accendevano quella sera, indicava
+l'orizzonte e diceva che, da qualche parte là fuori, la vecchia nave
+stava ancora navigando.`,
+
+	"pt": `Era uma noite escura e tempestuosa, e o velho marinheiro contava sua
+história a quem quisesse ouvi-lo. Dizia que o mar se lembra de tudo o que
+já engoliu, e que o vento carrega as vozes daqueles que nunca voltaram
+para casa. Os jovens pescadores riam dele, mas as crianças acreditavam em
+cada palavra. Com o passar dos anos, a história mudava um pouco cada vez
+que ele a contava, como toda história muda quando passa de uma boca para
+outra. Ainda assim, o marinheiro insistia que o coração da história nunca
+mudava: o mar dá, e o mar tira, e não explica nada a ninguém. Quando as
+luzes do porto se acendiam naquela noite, ele apontava para o horizonte e
+dizia que, em algum lugar lá fora, o velho navio ainda estava navegando.`,
+
+	"nl": `Het was een donkere en stormachtige nacht, en de oude zeeman vertelde
+zijn verhaal aan iedereen die wilde luisteren. Hij zei dat de zee zich
+alles herinnert wat ze ooit heeft verzwolgen, en dat de wind de stemmen
+draagt van hen die nooit meer thuiskwamen. De jonge vissers lachten om
+hem, maar de kinderen geloofden elk woord. In de loop der jaren
+veranderde het verhaal elke keer een beetje als hij het vertelde, zoals
+ieder verhaal verandert wanneer het van de ene mond naar de andere gaat.
+Toch hield de zeeman vol dat de kern van het verhaal nooit veranderde: de
+zee geeft, en de zee neemt, en ze legt zich aan niemand uit. Toen de
+lichten van de haven die avond aangingen, wees hij naar de horizon en zei
+dat het oude schip daar ergens nog steeds voer.`,
+
+	"ru": `Была тёмная и грозовая ночь, и старый моряк рассказывал свою историю
+каждому, кто хотел его слушать. Он говорил, что море помнит всё, что
+когда-либо поглотило, и что ветер несёт голоса тех, кто никогда не
+вернулся домой. Молодые рыбаки смеялись над ним, но дети верили каждому
+слову. С годами история немного менялась каждый раз, когда он её
+рассказывал, как меняется любая история, переходя из одних уст в другие.
+И всё же моряк настаивал, что сердце этой истории никогда не менялось:
+море даёт, и море забирает, и оно никому ничего не объясняет. Когда в тот
+вечер зажглись огни гавани, он указывал на горизонт и говорил, что
+где-то там старый корабль всё ещё плывёт.`,
+
+	"zh": `那是一个漆黑而暴风雨的夜晚，老水手向每一个愿意听他讲话的人讲述了他的故事。
+他说大海记得它曾经吞没过的一切，风带着那些再也没有回家的人的声音。年轻的渔民们
+嘲笑他，可是孩子们相信他说的每一句话。多年以来，这个故事每次讲述的时候都会有一点
+变化，就像每一个故事从一张嘴传到另一张嘴的时候都会发生变化一样。尽管如此，老水手
+坚持说这个故事的核心从来没有变过：大海给予，大海也夺走，它不会向任何人解释自己。
+那天晚上，当港口的灯光亮起来的时候，他指着地平线说，在那遥远的地方，那艘古老的船
+仍然在航行，等待着一群再也不会回来的船员。没有人能说清楚这些话是不是真的，也没有
+人真的想知道答案。`,
+
+	"ja": `それは暗く嵐の夜だった。年老いた船乗りは、聞いてくれる人なら誰にでも自分の
+物語を語った。彼は、海はそれが飲み込んだすべてを覚えていると言い、風は二度と
+家に帰らなかった人々の声を運んでいると言った。若い漁師たちは彼を笑ったが、子供
+たちは彼の言葉をひとつひとつ信じていた。長い年月の間に、この物語は語られるたび
+に少しずつ変わっていった。どんな物語も、ひとつの口から別の口へと伝わるときには
+そうやって変わっていくものだ。それでも、船乗りはこの物語の核心だけは決して変わ
+らないのだと言い張った。海は与え、海は奪う、そして海は誰にも自分の理由を説明し
+ない。その夜、港の明かりがともると、彼は水平線の方を指さして、あの遠い場所では
+古い船が今もまだ航海を続けていると言った。`,
+
+	"ko": `그날은 어둡고 폭풍우가 몰아치는 밤이었다. 늙은 선원은 자신의 이야기를 들어줄
+사람이라면 누구에게나 그 이야기를 들려주었다. 그는 바다가 자신이 삼킨 모든 것을
+기억하고 있으며, 바람은 다시는 집으로 돌아오지 못한 사람들의 목소리를 실어 나른
+다고 말했다. 젊은 어부들은 그를 비웃었지만, 아이들은 그의 말 한마디 한마디를
+믿었다. 세월이 흐르면서 그 이야기는 말할 때마다 조금씩 달라졌는데, 모든 이야기가
+한 입에서 다른 입으로 전해질 때 그렇게 달라지는 것과 같았다. 그럼에도 선원은 이
+이야기의 핵심만은 결코 변하지 않는다고 주장했다. 바다는 주고, 바다는 빼앗으며,
+바다는 누구에게도 자신을 설명하지 않는다. 그날 저녁 항구의 불빛이 켜지자, 그는
+수평선을 가리키며 저 먼 곳에서 낡은 배가 아직도 항해하고 있다고 말했다.`,
+}
+
+// langOrder fixes output order across runs purely for readable diffs.
+var langOrder = []string{"en", "es", "fr", "de", "it", "pt", "nl", "ru", "zh", "ja", "ko"}
+
+func main() {
+	outDir := "internal/formatter/langdata"
+	for _, lang := range langOrder {
+		text, ok := corpora[lang]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "no corpus for %q\n", lang)
+			os.Exit(1)
+		}
+
+		profile := rankedProfile(text)
+
+		lines := make([]string, len(profile))
+		for i, trigram := range profile {
+			// Encoding space as _ only round-trips cleanly if trigrams never
+			// contain a literal underscore themselves; enforce that here
+			// rather than let loadTrigramProfile silently decode a bogus
+			// trigram later.
+			if strings.Contains(trigram, "_") {
+				fmt.Fprintf(os.Stderr, "%s: trigram %q already contains _, can't encode unambiguously\n", lang, trigram)
+				os.Exit(1)
+			}
+			lines[i] = strings.ReplaceAll(trigram, " ", "_")
+		}
+
+		path := filepath.Join(outDir, lang+".trigrams")
+		content := strings.Join(lines, "\n") + "\n"
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "write %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s: %d trigrams\n", lang, len(profile))
+	}
+}
@@ -3,14 +3,22 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"gopkg.in/yaml.v3"
 	"web-to-epub-go/internal/config"
+	"web-to-epub-go/internal/pipeline"
 	"web-to-epub-go/internal/scraper"
+	"web-to-epub-go/internal/server"
+	"web-to-epub-go/internal/sources"
 )
 
 const (
@@ -19,14 +27,38 @@ const (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "resume" {
+		runResumeCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "rules" {
+		runRulesCommand(os.Args[2:])
+		return
+	}
+
 	var (
-		configFile = flag.String("config", "config.yaml", "Path to configuration file (YAML or JSON)")
-		outputPath = flag.String("output", "", "Output directory (overrides config)")
-		dryRun     = flag.Bool("dry-run", false, "Parse config and show plan without scraping")
-		testURL    = flag.String("test", "", "Test extraction on a single URL")
-		verbose    = flag.Bool("verbose", false, "Enable verbose output")
-		version    = flag.Bool("version", false, "Show version information")
-		help       = flag.Bool("help", false, "Show help message")
+		configFile  = flag.String("config", "config.yaml", "Path to configuration file (YAML or JSON)")
+		outputPath  = flag.String("output", "", "Output directory (overrides config)")
+		dryRun      = flag.Bool("dry-run", false, "Parse config and show plan without scraping")
+		testURL     = flag.String("test", "", "Test extraction on a single URL")
+		progress    = flag.String("progress", "bar", "Progress output: bar, json, or none")
+		force       = flag.Bool("force", false, "Re-fetch chapters even if a saved state entry exists")
+		checkpoint  = flag.String("checkpoint", "", "Path to the checkpoint state database (overrides scraping.statePath)")
+		resume      = flag.Bool("resume", false, "Resume from the checkpoint, skipping completed chapters and re-fetching only changed ones")
+		fromCache   = flag.Bool("from-cache", false, "Rebuild the output from the checkpoint without hitting the network")
+		reset       = flag.Bool("reset", false, "Wipe the checkpoint database before running")
+		rulesDir    = flag.String("rules-dir", "", "Directory of site rule packs (YAML/JSON) to merge into config")
+		scrapersDir = flag.String("scrapers-dir", "", "Directory of post-processing scraper rules (YAML/JSON) run against every chapter's title/content")
+		verbose     = flag.Bool("verbose", false, "Enable verbose output")
+		noTUI       = flag.Bool("no-tui", false, "Disable the interactive progress bars and log plain lines instead (implied by --verbose)")
+		source      = flag.String("source", "", "Use a built-in catalog source instead of --config (standard-ebooks, gutenberg, globalgrey)")
+		list        = flag.Bool("list", false, "With --source, print the catalog's available titles and exit")
+		title       = flag.String("title", "", "With --source, resolve this title and scrape/download it")
+		serve       = flag.String("serve", "", "Start the HTTP UI on this address (e.g. :8080) instead of scraping")
+		epubBackend = flag.String("epub-backend", "", "EPUB builder to use: legacy (default) or go-epub")
+		theme       = flag.String("theme", "", "Bundled EPUB stylesheet: serif (default), sans, dark, or dyslexic")
+		version     = flag.Bool("version", false, "Show version information")
+		help        = flag.Bool("help", false, "Show help message")
 	)
 
 	flag.Usage = func() {
@@ -54,11 +86,38 @@ Examples:
   # Override output directory
   %s --config config.yaml --output ./my-books
 
+  # Rebuild an EPUB from a saved state store without re-fetching
+  %s resume ./state.db --config config.yaml
+
+  # Resume an interrupted scrape, re-fetching only chapters whose ETag changed
+  %s --config config.yaml --checkpoint ./state.db --resume
+
+  # Rebuild the EPUB from a checkpoint without touching the network
+  %s --config config.yaml --checkpoint ./state.db --from-cache
+
+  # See which rule pack applies to a URL and what config it produces
+  %s rules test "https://www.royalroad.com/fiction/1/chapter/1"
+
+  # List titles available from a built-in catalog source
+  %s --source standard-ebooks --list
+
+  # Fetch a title from a built-in catalog source by name
+  %s --source gutenberg --title "Frankenstein"
+
+  # Apply post-processing rules to every chapter's title/content
+  %s --config config.yaml --scrapers-dir ./scrapers
+
+  # Start the HTTP UI for browsing, testing, and running scrapes
+  %s --serve :8080
+
+  # Generate with a bundled dark theme instead of the default stylesheet
+  %s --config config.yaml --theme dark
+
 Configuration:
   See examples/ directory for sample configuration files.
   Supported formats: YAML (.yaml, .yml) and JSON (.json)
 
-`, os.Args[0], os.Args[0], os.Args[0], os.Args[0])
+`, os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0])
 	}
 
 	flag.Parse()
@@ -73,11 +132,21 @@ Configuration:
 		os.Exit(0)
 	}
 
+	if *source != "" {
+		runSourceCommand(*source, *list, *title, *outputPath, *progress, *verbose || *noTUI)
+		return
+	}
+
+	if *serve != "" {
+		runServeCommand(*serve, *rulesDir, *scrapersDir, *outputPath)
+		return
+	}
+
 	if _, err := os.Stat(*configFile); os.IsNotExist(err) {
 		log.Fatalf("❌ Configuration file not found: %s\n\nRun '%s --help' for usage information.", *configFile, os.Args[0])
 	}
 
-	cfg, err := config.LoadConfig(*configFile)
+	cfg, err := config.LoadConfigWithRules(*configFile, *rulesDir)
 	if err != nil {
 		log.Fatalf("❌ Failed to load configuration: %v", err)
 	}
@@ -85,6 +154,17 @@ Configuration:
 	if *outputPath != "" {
 		cfg.Output.OutputPath = *outputPath
 	}
+	if *checkpoint != "" {
+		cfg.Scraping.StatePath = *checkpoint
+	}
+	if (*resume || *fromCache) && cfg.Scraping.StatePath == "" {
+		log.Fatalf("❌ --resume/--from-cache require --checkpoint or scraping.statePath to be set")
+	}
+	if *reset && cfg.Scraping.StatePath != "" {
+		if err := os.Remove(cfg.Scraping.StatePath); err != nil && !os.IsNotExist(err) {
+			log.Fatalf("❌ Failed to reset checkpoint %s: %v", cfg.Scraping.StatePath, err)
+		}
+	}
 
 	printHeader(cfg)
 
@@ -98,14 +178,265 @@ Configuration:
 		log.Fatalf("❌ Failed to create scraper: %v", err)
 	}
 
+	scraperRules, err := pipeline.LoadRulesDir(*scrapersDir)
+	if err != nil {
+		log.Fatalf("❌ Failed to load scrapers directory: %v", err)
+	}
+	ws.SetPipelineRules(scraperRules)
+
 	if *testURL != "" {
 		runTestMode(ws, *testURL, *verbose)
 		return
 	}
 
+	if *fromCache {
+		if err := ws.Resume(cfg.Scraping.StatePath); err != nil {
+			log.Fatalf("❌ Failed to resume from %s: %v", cfg.Scraping.StatePath, err)
+		}
+		fmt.Printf("\n📖 Loaded %d chapters from %s\n", len(ws.GetChapters()), cfg.Scraping.StatePath)
+		fmt.Printf("📦 Generating %s...\n", strings.ToUpper(cfg.Output.Format))
+		if err := ws.Generate(cfg.Output.OutputPath); err != nil {
+			log.Fatalf("❌ Output generation failed: %v", err)
+		}
+		fmt.Println("✅ Done!")
+		return
+	}
+
+	ws.SetProgressReporter(newProgressReporter(*progress, *verbose || *noTUI))
+	ws.SetForce(*force)
+	ws.SetEPUBOptions(*epubBackend, *theme)
 	runFullScrape(ws, cfg, *verbose)
 }
 
+// runResumeCommand implements `web-to-epub resume <state.db>`, rebuilding an
+// EPUB from a saved state store without touching the network.
+func runResumeCommand(args []string) {
+	fs := flag.NewFlagSet("resume", flag.ExitOnError)
+	configFile := fs.String("config", "config.yaml", "Path to configuration file (YAML or JSON)")
+	outputPath := fs.String("output", "", "Output directory (overrides config)")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		log.Fatalf("❌ Usage: %s resume <state.db> [--config config.yaml]", AppName)
+	}
+	statePath := fs.Arg(0)
+	fs.Parse(fs.Args()[1:])
+
+	cfg, err := config.LoadConfig(*configFile)
+	if err != nil {
+		log.Fatalf("❌ Failed to load configuration: %v", err)
+	}
+
+	if *outputPath != "" {
+		cfg.Output.OutputPath = *outputPath
+	}
+
+	ws, err := scraper.NewWebScraper(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to create scraper: %v", err)
+	}
+
+	if err := ws.Resume(statePath); err != nil {
+		log.Fatalf("❌ Failed to resume from %s: %v", statePath, err)
+	}
+
+	fmt.Printf("\n📖 Loaded %d chapters from %s\n", len(ws.GetChapters()), statePath)
+	fmt.Printf("📦 Generating %s...\n", strings.ToUpper(cfg.Output.Format))
+	if err := ws.Generate(cfg.Output.OutputPath); err != nil {
+		log.Fatalf("❌ Output generation failed: %v", err)
+	}
+	fmt.Println("✅ Done!")
+}
+
+// runRulesCommand implements `web-to-epub rules <subcommand>`. Currently
+// the only subcommand is `test <url>`, which reports which rule pack
+// matches a URL and prints the config that results from merging it in.
+func runRulesCommand(args []string) {
+	if len(args) < 1 || args[0] != "test" {
+		log.Fatalf("❌ Usage: %s rules test <url> [--rules-dir dir] [--config config.yaml]", AppName)
+	}
+
+	fs := flag.NewFlagSet("rules test", flag.ExitOnError)
+	rulesDir := fs.String("rules-dir", "", "Directory of site rule packs (YAML/JSON) to check before the builtin registry")
+	configFile := fs.String("config", "", "Base configuration file to merge the rule into (optional)")
+	fs.Parse(args[1:])
+
+	if fs.NArg() < 1 {
+		log.Fatalf("❌ Usage: %s rules test <url> [--rules-dir dir] [--config config.yaml]", AppName)
+	}
+	testURL := fs.Arg(0)
+	fs.Parse(fs.Args()[1:])
+
+	rules, err := config.LoadRulesDir(*rulesDir)
+	if err != nil {
+		log.Fatalf("❌ Failed to load rules directory: %v", err)
+	}
+
+	rule, err := config.MatchRule(rules, testURL)
+	if err != nil {
+		log.Fatalf("❌ Failed to match rule: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	if *configFile != "" {
+		cfg, err = config.LoadConfig(*configFile)
+		if err != nil {
+			log.Fatalf("❌ Failed to load configuration: %v", err)
+		}
+	}
+	cfg.Scraping.StartURL = testURL
+
+	if rule == nil {
+		fmt.Println("❌ No rule matched this URL")
+		return
+	}
+
+	fmt.Printf("✅ Matched rule: %s\n\n", rule.Name)
+	raw := &config.Config{}
+	config.MergeRule(cfg, raw, rule)
+
+	merged, err := yaml.Marshal(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to render merged configuration: %v", err)
+	}
+	fmt.Println(string(merged))
+}
+
+// runSourceCommand implements `web-to-epub --source <name> [--list|--title]`,
+// scraping from a built-in catalog instead of a hand-written config.
+func runSourceCommand(name string, list bool, title, outputPath, progress string, plainFallback bool) {
+	driver, ok := sources.Get(name)
+	if !ok {
+		log.Fatalf("❌ Unknown source %q. Available: %s", name, strings.Join(sources.Names(), ", "))
+	}
+
+	ctx := context.Background()
+
+	if list {
+		titles, err := driver.List(ctx)
+		if err != nil {
+			log.Fatalf("❌ Failed to list %s catalog: %v", name, err)
+		}
+		for _, t := range titles {
+			if t.Author != "" {
+				fmt.Printf("%s, by %s\n", t.Name, t.Author)
+			} else {
+				fmt.Println(t.Name)
+			}
+		}
+		return
+	}
+
+	if title == "" {
+		log.Fatalf("❌ --source requires --list or --title")
+	}
+
+	resolved, err := driver.Resolve(ctx, title)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	if outputPath == "" {
+		outputPath = "./books"
+	}
+	if err := os.MkdirAll(outputPath, 0o755); err != nil {
+		log.Fatalf("❌ Failed to create output directory: %v", err)
+	}
+
+	if resolved.EPUBURL != "" {
+		dest := filepath.Join(outputPath, sanitizeFilename(resolved.Name)+".epub")
+		fmt.Printf("📥 Downloading pre-built EPUB for %q...\n", resolved.Name)
+		if err := downloadFile(ctx, resolved.EPUBURL, dest); err != nil {
+			log.Fatalf("❌ Failed to download %s: %v", resolved.EPUBURL, err)
+		}
+		fmt.Printf("✅ Saved %s\n", dest)
+		return
+	}
+
+	fmt.Printf("ℹ %s has no pre-built EPUB; falling back to a normal chapter scrape\n", resolved.Name)
+	cfg, err := driver.BuildConfig(resolved)
+	if err != nil {
+		log.Fatalf("❌ Failed to build a scrape config for %q: %v", resolved.Name, err)
+	}
+	cfg.Output.OutputPath = outputPath
+
+	ws, err := scraper.NewWebScraper(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to create scraper: %v", err)
+	}
+	ws.SetProgressReporter(newProgressReporter(progress, plainFallback))
+
+	runFullScrape(ws, cfg, plainFallback)
+}
+
+// runServeCommand implements `web-to-epub --serve <addr>`, starting the HTTP
+// UI for validating configs, test-extracting a chapter, running scrapes
+// with live progress, and browsing previously generated output files.
+// outputPath (--output) is where /library lists and /download serves files
+// from, defaulting to the same "./books" DefaultConfig uses when unset.
+func runServeCommand(addr, rulesDir, scrapersDir, outputPath string) {
+	if outputPath == "" {
+		outputPath = config.DefaultConfig().Output.OutputPath
+	}
+	srv := server.New(rulesDir, scrapersDir, outputPath)
+	fmt.Printf("🌐 Serving web-to-epub UI on http://%s\n", addr)
+	if err := http.ListenAndServe(addr, srv.Handler()); err != nil {
+		log.Fatalf("❌ Server failed: %v", err)
+	}
+}
+
+// downloadFile streams rawURL's body to dest.
+func downloadFile(ctx context.Context, rawURL, dest string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, resp.Body)
+	return err
+}
+
+// sanitizeFilename strips characters that aren't safe in a file
+// name from an ebook title, for use as the downloaded EPUB's filename.
+func sanitizeFilename(name string) string {
+	replacer := strings.NewReplacer("/", "-", "\\", "-", ":", "-", "\"", "", "?", "", "*", "", "<", "", ">", "", "|", "")
+	return strings.TrimSpace(replacer.Replace(name))
+}
+
+// newProgressReporter builds the ProgressReporter selected via --progress.
+// plainFallback (set by --verbose or --no-tui) downgrades the default "bar"
+// mode to plain log lines, since an interactive TUI and verbose/CI logging
+// don't mix on the same stdout.
+func newProgressReporter(mode string, plainFallback bool) scraper.ProgressReporter {
+	switch mode {
+	case "json":
+		return scraper.NewJSONReporter(os.Stdout)
+	case "none":
+		return scraper.NoOpReporter{}
+	default:
+		if plainFallback {
+			return scraper.NewPlainReporter(os.Stdout)
+		}
+		return scraper.NewTerminalReporter()
+	}
+}
+
 func printHeader(cfg *config.Config) {
 	fmt.Println()
 	fmt.Println(strings.Repeat("═", 60))
@@ -145,6 +476,23 @@ func printDryRunSummary(cfg *config.Config) {
 	case "toc":
 		fmt.Printf("  • TOC URL: %s\n", cfg.Navigation.TOCUrl)
 		fmt.Printf("  • TOC Link Selector: %s\n", cfg.Navigation.TOCLinkSelector)
+	case "feed":
+		fmt.Printf("  • Feed URL: %s\n", cfg.Navigation.FeedURL)
+		if cfg.Navigation.MaxChapters > 0 {
+			fmt.Printf("  • Max Chapters: %d\n", cfg.Navigation.MaxChapters)
+		}
+	case "sitemap":
+		if cfg.Navigation.SitemapURL != "" {
+			fmt.Printf("  • Sitemap URL: %s\n", cfg.Navigation.SitemapURL)
+		} else {
+			fmt.Println("  • Sitemap URL: (discovered from robots.txt)")
+		}
+		if cfg.Navigation.SitemapURLFilter != "" {
+			fmt.Printf("  • Sitemap Pattern: %s\n", cfg.Navigation.SitemapURLFilter)
+		}
+		if cfg.Navigation.MaxChapters > 0 {
+			fmt.Printf("  • Max Chapters: %d\n", cfg.Navigation.MaxChapters)
+		}
 	}
 
 	fmt.Println("\n🔬 Content Detection:")
@@ -171,7 +519,7 @@ func runTestMode(ws *scraper.WebScraper, testURL string, verbose bool) {
 	fmt.Printf("📍 URL: %s\n", testURL)
 	fmt.Println(strings.Repeat("─", 40))
 
-	chapter, err := ws.ScrapeTest(testURL)
+	chapter, steps, err := ws.ScrapeTest(testURL)
 	if err != nil {
 		log.Fatalf("❌ Test failed: %v", err)
 	}
@@ -181,6 +529,20 @@ func runTestMode(ws *scraper.WebScraper, testURL string, verbose bool) {
 	fmt.Printf("📊 Content length: %d characters\n", len(chapter.Content))
 
 	if verbose {
+		if len(steps) > 0 {
+			fmt.Println("\n🔧 Pipeline rules (before → after):")
+			fmt.Println(strings.Repeat("─", 40))
+			for _, step := range steps {
+				fmt.Printf("[%s] %s:\n  before: %s\n  after:  %s\n", step.Rule, step.Target, truncate(step.Before), truncate(step.After))
+			}
+		}
+		if len(chapter.Metadata) > 0 {
+			fmt.Println("\n🏷  Extracted metadata:")
+			for k, v := range chapter.Metadata {
+				fmt.Printf("  %s: %s\n", k, v)
+			}
+		}
+
 		fmt.Println("\n📄 Content Preview (first 500 chars):")
 		fmt.Println(strings.Repeat("─", 40))
 		preview := chapter.Content
@@ -193,6 +555,15 @@ func runTestMode(ws *scraper.WebScraper, testURL string, verbose bool) {
 	fmt.Println()
 }
 
+// truncate shortens s for single-line before/after display in --test --verbose.
+func truncate(s string) string {
+	const maxLen = 120
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen] + "..."
+}
+
 func runFullScrape(ws *scraper.WebScraper, cfg *config.Config, verbose bool) {
 	fmt.Println("\n⏳ Starting scrape...")
 	fmt.Println()
@@ -203,9 +574,9 @@ func runFullScrape(ws *scraper.WebScraper, cfg *config.Config, verbose bool) {
 
 	ws.PrintSummary()
 
-	fmt.Println("\n📦 Generating EPUB...")
-	if err := ws.GenerateEPUB(cfg.Output.OutputPath); err != nil {
-		log.Fatalf("❌ EPUB generation failed: %v", err)
+	fmt.Printf("\n📦 Generating %s...\n", strings.ToUpper(cfg.Output.Format))
+	if err := ws.Generate(cfg.Output.OutputPath); err != nil {
+		log.Fatalf("❌ Output generation failed: %v", err)
 	}
 
 	fmt.Println("\n" + strings.Repeat("═", 60))
@@ -12,12 +12,22 @@ import (
 
 // LoadConfig loads configuration from a YAML or JSON file
 func LoadConfig(path string) (*Config, error) {
+	return LoadConfigWithRules(path, "")
+}
+
+// LoadConfigWithRules loads configuration from a YAML or JSON file, then
+// merges in the first rule pack (from rulesDir plus the builtin registry)
+// whose matcher matches scraping.startUrl. Rule fields only fill in ones
+// the user left unset in their file; an empty rulesDir still checks the
+// builtin registry. See Rule for the merge semantics.
+func LoadConfigWithRules(path, rulesDir string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
 	cfg := DefaultConfig()
+	raw := &Config{}
 
 	ext := strings.ToLower(filepath.Ext(path))
 	switch ext {
@@ -25,15 +35,34 @@ func LoadConfig(path string) (*Config, error) {
 		if err := yaml.Unmarshal(data, cfg); err != nil {
 			return nil, fmt.Errorf("failed to parse YAML config: %w", err)
 		}
+		_ = yaml.Unmarshal(data, raw)
 	case ".json":
 		if err := json.Unmarshal(data, cfg); err != nil {
 			return nil, fmt.Errorf("failed to parse JSON config: %w", err)
 		}
+		_ = json.Unmarshal(data, raw)
 	default:
 		if err := yaml.Unmarshal(data, cfg); err != nil {
 			if err := json.Unmarshal(data, cfg); err != nil {
 				return nil, fmt.Errorf("failed to parse config (tried YAML and JSON): %w", err)
 			}
+			_ = json.Unmarshal(data, raw)
+		} else {
+			_ = yaml.Unmarshal(data, raw)
+		}
+	}
+
+	if cfg.Scraping.StartURL != "" {
+		rules, err := LoadRulesDir(rulesDir)
+		if err != nil {
+			return nil, err
+		}
+		rule, err := MatchRule(rules, cfg.Scraping.StartURL)
+		if err != nil {
+			return nil, err
+		}
+		if rule != nil {
+			MergeRule(cfg, raw, rule)
 		}
 	}
 
@@ -53,8 +82,9 @@ func ValidateConfig(cfg *Config) error {
 		return fmt.Errorf("book.author is required")
 	}
 
-	if cfg.Scraping.StartURL == "" && cfg.Navigation.Method != "toc" {
-		return fmt.Errorf("scraping.startUrl is required (unless using toc navigation)")
+	if cfg.Scraping.StartURL == "" && cfg.Navigation.Method != "toc" && cfg.Navigation.Method != "feed" &&
+		!(cfg.Navigation.Method == "sitemap" && cfg.Navigation.SitemapURL != "") {
+		return fmt.Errorf("scraping.startUrl is required (unless using toc or feed navigation, or sitemap navigation with navigation.sitemapUrl set)")
 	}
 
 	switch cfg.Navigation.Method {
@@ -76,8 +106,19 @@ func ValidateConfig(cfg *Config) error {
 		if cfg.Navigation.TOCLinkSelector == "" {
 			return fmt.Errorf("navigation.tocLinkSelector is required for toc method")
 		}
+	case "feed":
+		if cfg.Navigation.FeedURL == "" {
+			return fmt.Errorf("navigation.feedUrl is required for feed method")
+		}
+		if cfg.Navigation.FeedOrder != "" && cfg.Navigation.FeedOrder != "asc" && cfg.Navigation.FeedOrder != "desc" {
+			return fmt.Errorf("navigation.feedOrder must be asc or desc")
+		}
+	case "sitemap":
+		if cfg.Navigation.SitemapURL == "" && cfg.Scraping.StartURL == "" {
+			return fmt.Errorf("navigation.sitemapUrl or scraping.startUrl is required for sitemap method")
+		}
 	default:
-		return fmt.Errorf("unknown navigation method: %s (valid: url_pattern, next_link, toc)", cfg.Navigation.Method)
+		return fmt.Errorf("unknown navigation method: %s (valid: url_pattern, next_link, toc, feed, sitemap)", cfg.Navigation.Method)
 	}
 
 	validStrategies := map[string]bool{
@@ -85,6 +126,7 @@ func ValidateConfig(cfg *Config) error {
 		"text_density": true,
 		"xpath_regex":  true,
 		"dom_position": true,
+		"readability":  true,
 		"hybrid":       true,
 	}
 	if !validStrategies[cfg.ContentDetection.Strategy] {
@@ -103,6 +145,22 @@ func ValidateConfig(cfg *Config) error {
 		return fmt.Errorf("output.format must be 'epub' or 'pdf'")
 	}
 
+	if cfg.Output.Format == "pdf" {
+		validPageSizes := map[string]bool{"A3": true, "A4": true, "A5": true, "Letter": true, "Legal": true}
+		if !validPageSizes[cfg.Output.PDF.PageSize] {
+			return fmt.Errorf("output.pdf.pageSize must be one of A3, A4, A5, Letter, Legal")
+		}
+		if cfg.Output.PDF.FontSize <= 0 {
+			return fmt.Errorf("output.pdf.fontSize must be > 0")
+		}
+		if cfg.Output.PDF.TitleSize <= 0 {
+			return fmt.Errorf("output.pdf.titleSize must be > 0")
+		}
+		if cfg.Output.PDF.MarginMM < 0 {
+			return fmt.Errorf("output.pdf.marginMm must be >= 0")
+		}
+	}
+
 	if cfg.Scraping.Polite.DelayMS < 0 {
 		return fmt.Errorf("scraping.polite.delayMs must be >= 0")
 	}
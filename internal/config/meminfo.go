@@ -0,0 +1,78 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// defaultCacheSizeFraction is the fraction of total system memory
+// DefaultConfig budgets for ScrapingConfig.CacheSizeBytes when /proc/meminfo
+// is readable, rather than a single fixed size that's wasteful on a
+// memory-constrained host and overly conservative on a large one.
+const defaultCacheSizeFraction = 0.01
+
+// fallbackCacheSizeBytes is used when /proc/meminfo can't be read (non-Linux
+// platforms, sandboxes without /proc, ...) or its MemTotal line can't be
+// parsed.
+const fallbackCacheSizeBytes = 50 * 1024 * 1024
+
+// minCacheSizeBytes and maxCacheSizeBytes bound the fraction-of-memory result
+// so a very small or very large host still gets a sane cache size.
+const (
+	minCacheSizeBytes = 16 * 1024 * 1024
+	maxCacheSizeBytes = 512 * 1024 * 1024
+)
+
+// defaultCacheSizeBytes computes DefaultConfig's ScrapingConfig.CacheSizeBytes
+// as defaultCacheSizeFraction of total system memory, clamped to
+// [minCacheSizeBytes, maxCacheSizeBytes], falling back to
+// fallbackCacheSizeBytes if total memory can't be determined.
+func defaultCacheSizeBytes() int64 {
+	total, err := readMemTotalBytes("/proc/meminfo")
+	if err != nil {
+		return fallbackCacheSizeBytes
+	}
+
+	size := int64(float64(total) * defaultCacheSizeFraction)
+	if size < minCacheSizeBytes {
+		size = minCacheSizeBytes
+	}
+	if size > maxCacheSizeBytes {
+		size = maxCacheSizeBytes
+	}
+	return size
+}
+
+// readMemTotalBytes parses the "MemTotal:" line /proc/meminfo starts with on
+// Linux, returning total system memory in bytes.
+func readMemTotalBytes(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "MemTotal:") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return 0, fmt.Errorf("unexpected MemTotal line in %s: %q", path, line)
+		}
+		kb, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid MemTotal value in %s: %w", path, err)
+		}
+		return kb * 1024, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("MemTotal not found in %s", path)
+}
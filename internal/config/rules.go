@@ -0,0 +1,230 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rule is a shareable, site-specific scraping configuration bundle. It
+// matches a chapter URL by host or regex and supplies partial Navigation,
+// ContentDetection, and ChapterExtract overrides, so the community can add
+// support for a new site without touching Go code.
+type Rule struct {
+	Name             string                 `yaml:"name"`
+	MatchHost        string                 `yaml:"matchHost,omitempty"`
+	MatchURLRegex    string                 `yaml:"matchUrlRegex,omitempty"`
+	Navigation       NavigationConfig       `yaml:"navigation,omitempty"`
+	ContentDetection ContentDetectionConfig `yaml:"contentDetection,omitempty"`
+	ChapterExtract   ChapterExtractConfig   `yaml:"chapterExtraction,omitempty"`
+}
+
+// Matches reports whether the rule applies to the given chapter/start URL.
+// A regex matcher takes precedence over a host matcher when both are set.
+func (r *Rule) Matches(rawURL string) (bool, error) {
+	if r.MatchURLRegex != "" {
+		re, err := regexp.Compile(r.MatchURLRegex)
+		if err != nil {
+			return false, fmt.Errorf("rule %q: invalid matchUrlRegex: %w", r.Name, err)
+		}
+		return re.MatchString(rawURL), nil
+	}
+
+	if r.MatchHost != "" {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return false, fmt.Errorf("rule %q: failed to parse URL: %w", r.Name, err)
+		}
+		host := strings.ToLower(u.Hostname())
+		want := strings.ToLower(r.MatchHost)
+		return host == want || strings.HasSuffix(host, "."+want), nil
+	}
+
+	return false, nil
+}
+
+// BuiltinRules returns the rule packs shipped with web-to-epub for a few
+// common web-fiction and wiki platforms.
+func BuiltinRules() []Rule {
+	return []Rule{
+		{
+			Name:      "royalroad",
+			MatchHost: "royalroad.com",
+			Navigation: NavigationConfig{
+				Method:           "next_link",
+				NextLinkSelector: ".nav-buttons a.btn:contains('Next')",
+			},
+			ContentDetection: ContentDetectionConfig{
+				Strategy:         "css_selector",
+				CSSSelector:      ".chapter-content",
+				ExcludeSelectors: []string{".portlet", ".author-note-portlet"},
+			},
+			ChapterExtract: ChapterExtractConfig{
+				TitleSelector: ".fic-header h1",
+			},
+		},
+		{
+			Name:      "wikisource",
+			MatchHost: "wikisource.org",
+			Navigation: NavigationConfig{
+				Method:          "toc",
+				TOCLinkSelector: "#mw-content-text a",
+			},
+			ContentDetection: ContentDetectionConfig{
+				Strategy:         "css_selector",
+				CSSSelector:      "#mw-content-text .mw-parser-output",
+				ExcludeSelectors: []string{".mw-editsection", ".noprint", "table.navbox"},
+			},
+			ChapterExtract: ChapterExtractConfig{
+				TitleSelector: "#firstHeading",
+			},
+		},
+		{
+			Name:          "generic-wordpress",
+			MatchURLRegex: `(?i)/wp-content/|/\d{4}/\d{2}/`,
+			Navigation: NavigationConfig{
+				Method:           "next_link",
+				NextLinkSelector: "a.next, .nav-next a",
+			},
+			ContentDetection: ContentDetectionConfig{
+				Strategy:         "css_selector",
+				CSSSelector:      ".entry-content, article .post-content",
+				ExcludeSelectors: []string{".sharedaddy", ".jp-relatedposts", ".comments-area"},
+			},
+			ChapterExtract: ChapterExtractConfig{
+				TitleSelector: "h1.entry-title",
+			},
+		},
+	}
+}
+
+// LoadRulesDir loads every YAML/JSON rule file in dir. A missing directory
+// is not an error: it simply yields no user-supplied rules.
+func LoadRulesDir(dir string) ([]Rule, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules directory: %w", err)
+	}
+
+	var rules []Rule
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rule file %s: %w", path, err)
+		}
+
+		var rule Rule
+		if ext == ".json" {
+			err = json.Unmarshal(data, &rule)
+		} else {
+			err = yaml.Unmarshal(data, &rule)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse rule file %s: %w", path, err)
+		}
+
+		if rule.Name == "" {
+			rule.Name = strings.TrimSuffix(entry.Name(), ext)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// MatchRule returns the first rule matching url, checking user-supplied
+// rules before the builtin registry so a local rule pack can shadow one of
+// web-to-epub's defaults.
+func MatchRule(rules []Rule, rawURL string) (*Rule, error) {
+	candidates := make([]Rule, 0, len(rules)+len(BuiltinRules()))
+	candidates = append(candidates, rules...)
+	candidates = append(candidates, BuiltinRules()...)
+
+	for i := range candidates {
+		matched, err := candidates[i].Matches(rawURL)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			return &candidates[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// MergeRule merges rule's Navigation, ContentDetection, and ChapterExtract
+// overrides into cfg, touching only the fields the user left unset in raw —
+// the config as literally written in their file, before defaults were
+// applied. User-specified values always win.
+func MergeRule(cfg *Config, raw *Config, rule *Rule) {
+	if raw.Navigation.Method == "" && rule.Navigation.Method != "" {
+		cfg.Navigation.Method = rule.Navigation.Method
+	}
+	if raw.Navigation.URLPattern == "" && rule.Navigation.URLPattern != "" {
+		cfg.Navigation.URLPattern = rule.Navigation.URLPattern
+	}
+	if raw.Navigation.NumberStart == 0 && rule.Navigation.NumberStart != 0 {
+		cfg.Navigation.NumberStart = rule.Navigation.NumberStart
+	}
+	if raw.Navigation.NumberEnd == 0 && rule.Navigation.NumberEnd != 0 {
+		cfg.Navigation.NumberEnd = rule.Navigation.NumberEnd
+	}
+	if raw.Navigation.NextLinkSelector == "" && rule.Navigation.NextLinkSelector != "" {
+		cfg.Navigation.NextLinkSelector = rule.Navigation.NextLinkSelector
+	}
+	if raw.Navigation.TOCUrl == "" && rule.Navigation.TOCUrl != "" {
+		cfg.Navigation.TOCUrl = rule.Navigation.TOCUrl
+	}
+	if raw.Navigation.TOCLinkSelector == "" && rule.Navigation.TOCLinkSelector != "" {
+		cfg.Navigation.TOCLinkSelector = rule.Navigation.TOCLinkSelector
+	}
+	if raw.Navigation.MaxChapters == 0 && rule.Navigation.MaxChapters != 0 {
+		cfg.Navigation.MaxChapters = rule.Navigation.MaxChapters
+	}
+
+	if raw.ContentDetection.Strategy == "" && rule.ContentDetection.Strategy != "" {
+		cfg.ContentDetection.Strategy = rule.ContentDetection.Strategy
+	}
+	if raw.ContentDetection.CSSSelector == "" && rule.ContentDetection.CSSSelector != "" {
+		cfg.ContentDetection.CSSSelector = rule.ContentDetection.CSSSelector
+	}
+	if len(raw.ContentDetection.ExcludeSelectors) == 0 && len(rule.ContentDetection.ExcludeSelectors) > 0 {
+		cfg.ContentDetection.ExcludeSelectors = rule.ContentDetection.ExcludeSelectors
+	}
+	if len(raw.ContentDetection.XPathPatterns) == 0 && len(rule.ContentDetection.XPathPatterns) > 0 {
+		cfg.ContentDetection.XPathPatterns = rule.ContentDetection.XPathPatterns
+	}
+	if len(raw.ContentDetection.RegexPatterns) == 0 && len(rule.ContentDetection.RegexPatterns) > 0 {
+		cfg.ContentDetection.RegexPatterns = rule.ContentDetection.RegexPatterns
+	}
+
+	if raw.ChapterExtract.TitleSelector == "" && rule.ChapterExtract.TitleSelector != "" {
+		cfg.ChapterExtract.TitleSelector = rule.ChapterExtract.TitleSelector
+	}
+	if raw.ChapterExtract.TitleRegex == "" && rule.ChapterExtract.TitleRegex != "" {
+		cfg.ChapterExtract.TitleRegex = rule.ChapterExtract.TitleRegex
+	}
+	if raw.ChapterExtract.TitleXPath == "" && rule.ChapterExtract.TitleXPath != "" {
+		cfg.ChapterExtract.TitleXPath = rule.ChapterExtract.TitleXPath
+	}
+}
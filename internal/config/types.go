@@ -18,6 +18,14 @@ type BookConfig struct {
 	Author      string `yaml:"author"`
 	Description string `yaml:"description"`
 	Cover       string `yaml:"cover,omitempty"`
+	// CoverFit is "contain" (default, scale to fit without cropping) or
+	// "cover" (center-crop to a 2:3 portrait before scaling).
+	CoverFit    string  `yaml:"coverFit,omitempty"`
+	Publisher   string  `yaml:"publisher,omitempty"`
+	Rights      string  `yaml:"rights,omitempty"`
+	PubDate     string  `yaml:"pubDate,omitempty"`
+	Series      string  `yaml:"series,omitempty"`
+	SeriesIndex float64 `yaml:"seriesIndex,omitempty"`
 }
 
 // ScrapingConfig controls scraping behavior
@@ -26,6 +34,12 @@ type ScrapingConfig struct {
 	Polite    PoliteConfig `yaml:"polite"`
 	UserAgent string       `yaml:"userAgent,omitempty"`
 	Timeout   int          `yaml:"timeout,omitempty"`
+	StatePath string       `yaml:"statePath,omitempty"`
+	// CacheSizeBytes bounds the in-memory LRU cache of fetched page bodies,
+	// used to conditionally revalidate (If-None-Match/If-Modified-Since)
+	// rather than re-download a page fetched again within the same process.
+	// The cache is not persisted between runs. 0 disables caching.
+	CacheSizeBytes int64 `yaml:"cacheSizeBytes,omitempty"`
 }
 
 // PoliteConfig controls rate limiting and ethical scraping
@@ -44,7 +58,38 @@ type NavigationConfig struct {
 	NextLinkSelector string `yaml:"nextLinkSelector,omitempty"`
 	TOCUrl           string `yaml:"tocUrl,omitempty"`
 	TOCLinkSelector  string `yaml:"tocLinkSelector,omitempty"`
+	// FeedURL is the RSS/Atom feed polled for chapter links when Method is
+	// "feed" - most sites publish these in reverse-chronological order, so
+	// the parsed entries are reversed to read oldest-first like a book.
+	FeedURL string `yaml:"feedUrl,omitempty"`
+	// FeedSince, if set, drops feed entries published/updated before this
+	// cutoff (RFC3339, "2006-01-02T15:04:05", or "2006-01-02"). Entries with
+	// no parseable date are kept, since there's no date to judge them by.
+	FeedSince string `yaml:"feedSince,omitempty"`
+	// FeedOrder is "asc" (default - oldest first, the reading order for a
+	// book) or "desc" (newest first, the feed's own natural order).
+	FeedOrder string `yaml:"feedOrder,omitempty"`
+	// SitemapURL is the sitemap.xml polled for chapter links when Method is
+	// "sitemap". If empty, it is discovered from the start URL's host's
+	// robots.txt "Sitemap:" directive.
+	SitemapURL string `yaml:"sitemapUrl,omitempty"`
+	// SitemapURLFilter is an optional regex that a sitemap <loc> URL must
+	// match to be treated as a chapter, filtering out unrelated pages
+	// (tag/category indexes, the site's homepage, ...) that share the
+	// sitemap with actual chapters.
+	SitemapURLFilter string `yaml:"sitemapUrlFilter,omitempty"`
 	MaxChapters      int    `yaml:"maxChapters,omitempty"`
+	// PartSelector is a CSS selector run against each chapter's extracted
+	// content looking for a volume/arc breadcrumb (e.g. "Volume 1 > Arc 2"),
+	// used to group chapters into a nested table of contents. The matched
+	// text is split on PartSeparator (default ">") into path segments, each
+	// trimmed of surrounding whitespace.
+	PartSelector string `yaml:"partSelector,omitempty"`
+	// PartRegex is an alternative to PartSelector: a regex whose capture
+	// groups, applied to the chapter title, each become one path segment.
+	// Used when PartSelector is empty or matches nothing.
+	PartRegex     string `yaml:"partRegex,omitempty"`
+	PartSeparator string `yaml:"partSeparator,omitempty"`
 }
 
 // ContentDetectionConfig controls how content is extracted from pages
@@ -82,10 +127,40 @@ type OutputConfig struct {
 	Format       string             `yaml:"format"`
 	OutputPath   string             `yaml:"outputPath"`
 	EPUBMetadata EPUBMetadataConfig `yaml:"epubMetadata,omitempty"`
+	Assets       AssetsConfig       `yaml:"assets,omitempty"`
+	// PDF controls page layout when Format is "pdf"; ignored otherwise.
+	PDF PDFConfig `yaml:"pdf,omitempty"`
+}
+
+// PDFConfig controls PDF-format output generation (see OutputConfig.Format).
+type PDFConfig struct {
+	// PageSize is one of A3, A4, A5, Letter, Legal.
+	PageSize   string  `yaml:"pageSize,omitempty"`
+	FontFamily string  `yaml:"fontFamily,omitempty"`
+	FontSize   float64 `yaml:"fontSize,omitempty"`
+	TitleSize  float64 `yaml:"titleSize,omitempty"`
+	// MarginMM is the page margin, in millimeters, applied on all four sides.
+	MarginMM float64 `yaml:"marginMm,omitempty"`
+	// IncludeTOC adds a chapter table of contents, with real page numbers, as
+	// the first page(s) of the book.
+	IncludeTOC bool `yaml:"includeToc"`
+}
+
+// AssetsConfig controls downloading and embedding of images referenced by chapter HTML
+type AssetsConfig struct {
+	// Enabled turns off image downloading/embedding entirely when false,
+	// leaving chapter HTML pointing at the original remote URLs.
+	Enabled          bool     `yaml:"enabled"`
+	MaxDimension     int      `yaml:"maxDimension,omitempty"`
+	MaxSizeBytes     int64    `yaml:"maxSizeBytes,omitempty"`
+	AllowedMimeTypes []string `yaml:"allowedMimeTypes,omitempty"`
+	LazyLoadAttrs    []string `yaml:"lazyLoadAttrs,omitempty"`
 }
 
 // EPUBMetadataConfig contains EPUB-specific metadata
 type EPUBMetadataConfig struct {
+	// Lang is an ISO 639-1 code, or "auto" to detect it from the scraped
+	// chapter text via formatter.DetectLanguage.
 	Lang       string `yaml:"lang"`
 	Rights     string `yaml:"rights"`
 	Publisher  string `yaml:"publisher,omitempty"`
@@ -101,8 +176,9 @@ func DefaultConfig() *Config {
 				MaxConcurrent:    1,
 				RespectRobotsTxt: true,
 			},
-			UserAgent: "Mozilla/5.0 (compatible; WebToEPUB/1.0)",
-			Timeout:   30,
+			UserAgent:      "Mozilla/5.0 (compatible; WebToEPUB/1.0)",
+			Timeout:        30,
+			CacheSizeBytes: defaultCacheSizeBytes(),
 		},
 		Navigation: NavigationConfig{
 			Method:      "url_pattern",
@@ -126,6 +202,21 @@ func DefaultConfig() *Config {
 				Lang:   "en",
 				Rights: "Personal use only",
 			},
+			Assets: AssetsConfig{
+				Enabled:          true,
+				MaxDimension:     1200,
+				MaxSizeBytes:     2 * 1024 * 1024,
+				AllowedMimeTypes: []string{"image/jpeg", "image/png", "image/gif", "image/webp", "image/svg+xml"},
+				LazyLoadAttrs:    []string{"data-src", "data-original", "data-lazy-src"},
+			},
+			PDF: PDFConfig{
+				PageSize:   "A4",
+				FontFamily: "Times",
+				FontSize:   11,
+				TitleSize:  16,
+				MarginMM:   20,
+				IncludeTOC: true,
+			},
 		},
 	}
 }
@@ -0,0 +1,188 @@
+package extractor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// ReadabilityStrategy extracts content using a Mozilla/arc90 Readability-style
+// scoring pass: candidate blocks are scored by tag, punctuation and length,
+// scores are propagated to ancestors, and the highest-scoring subtree (plus
+// its content-like siblings) is returned.
+type ReadabilityStrategy struct{}
+
+func (s *ReadabilityStrategy) Name() string {
+	return "readability"
+}
+
+var (
+	readabilityPositive = regexp.MustCompile(`(?i)article|body|content|entry|main|post|story`)
+	readabilityNegative = regexp.MustCompile(`(?i)comment|meta|footer|nav|sidebar|share|promo`)
+	readabilityUnlikely = regexp.MustCompile(`(?i)combx|comment|community|disqus|extra|foot|header|menu|remark|rss|shoutbox|sidebar|sponsor|ad-break|agegate|pagination|pager|popup`)
+	readabilityOkMaybe  = regexp.MustCompile(`(?i)article|body|content|entry|hentry|main|page|attachment|post|text|blog|story`)
+)
+
+func (s *ReadabilityStrategy) Extract(doc *goquery.Document, opts *ExtractionOptions) (string, error) {
+	body := doc.Find("body").First()
+	if body.Length() == 0 {
+		return "", fmt.Errorf("no body element found")
+	}
+
+	root := body.Clone()
+	root.Find("script, style, noscript, form, nav, aside").Remove()
+	removeUnlikelyCandidates(root)
+
+	scores := make(map[*html.Node]float64)
+
+	ensureBaseScore := func(n *html.Node) {
+		if _, ok := scores[n]; !ok {
+			scores[n] = tagBaseScore(n.Data)
+		}
+	}
+
+	root.Find("p, td, pre, div").Each(func(i int, sel *goquery.Selection) {
+		node := sel.Nodes[0]
+		text := strings.TrimSpace(sel.Text())
+		if len(text) < 25 {
+			return
+		}
+
+		score := strings.Count(text, ",") + 1
+		score += min(len(text)/100, 3)
+		score += int(classIDWeight(sel))
+
+		if parent := node.Parent; parent != nil {
+			ensureBaseScore(parent)
+			scores[parent] += float64(score)
+			if grandparent := parent.Parent; grandparent != nil {
+				ensureBaseScore(grandparent)
+				scores[grandparent] += float64(score) / 2
+			}
+		}
+	})
+
+	var topNode *html.Node
+	var topScore float64
+	for node, score := range scores {
+		candidate := goquery.NewDocumentFromNode(node).Selection
+		score *= 1 - linkDensity(candidate)
+		if topNode == nil || score > topScore {
+			topNode = node
+			topScore = score
+		}
+	}
+
+	if topNode == nil {
+		return "", fmt.Errorf("readability: no candidate content found")
+	}
+
+	var include []*html.Node
+	if topNode.Parent != nil {
+		for sibling := topNode.Parent.FirstChild; sibling != nil; sibling = sibling.NextSibling {
+			if sibling.Type != html.ElementNode {
+				continue
+			}
+			if sibling == topNode {
+				include = append(include, sibling)
+				continue
+			}
+
+			sel := goquery.NewDocumentFromNode(sibling).Selection
+			if score, ok := scores[sibling]; ok && score >= topScore*0.2 {
+				include = append(include, sibling)
+				continue
+			}
+
+			text := strings.TrimSpace(sel.Text())
+			if goquery.NodeName(sel) == "p" && len(text) >= 80 && linkDensity(sel) < 0.25 {
+				include = append(include, sibling)
+			}
+		}
+	} else {
+		include = []*html.Node{topNode}
+	}
+
+	var out strings.Builder
+	for _, node := range include {
+		h, err := goquery.NewDocumentFromNode(node).Html()
+		if err != nil {
+			continue
+		}
+		out.WriteString(h)
+		out.WriteString("\n")
+	}
+
+	content := strings.TrimSpace(out.String())
+	if content == "" {
+		return "", fmt.Errorf("readability: assembled content was empty")
+	}
+
+	return content, nil
+}
+
+func removeUnlikelyCandidates(sel *goquery.Selection) {
+	sel.Find("*").Each(func(i int, s *goquery.Selection) {
+		switch goquery.NodeName(s) {
+		case "html", "body":
+			return
+		}
+
+		class, _ := s.Attr("class")
+		id, _ := s.Attr("id")
+		combined := class + " " + id
+
+		if readabilityUnlikely.MatchString(combined) && !readabilityOkMaybe.MatchString(combined) {
+			s.Remove()
+		}
+	})
+}
+
+// tagBaseScore gives a candidate node's own tag a head start (or handicap)
+// before paragraph-derived scores are added on top, the same way Readability
+// treats a <div> as inherently more likely to be a content wrapper than a
+// <form> or <address>.
+func tagBaseScore(tag string) float64 {
+	switch tag {
+	case "div":
+		return 5
+	case "blockquote", "pre", "td":
+		return 3
+	case "address", "ol", "ul", "form":
+		return -3
+	default:
+		return 0
+	}
+}
+
+func classIDWeight(sel *goquery.Selection) float64 {
+	class, _ := sel.Attr("class")
+	id, _ := sel.Attr("id")
+	combined := class + " " + id
+
+	var weight float64
+	if readabilityPositive.MatchString(combined) {
+		weight += 25
+	}
+	if readabilityNegative.MatchString(combined) {
+		weight -= 25
+	}
+	return weight
+}
+
+func linkDensity(sel *goquery.Selection) float64 {
+	text := sel.Text()
+	if len(text) == 0 {
+		return 0
+	}
+
+	var anchorLen int
+	sel.Find("a").Each(func(i int, a *goquery.Selection) {
+		anchorLen += len(a.Text())
+	})
+
+	return float64(anchorLen) / float64(len(text))
+}
@@ -91,62 +91,123 @@ func (s *TextDensityStrategy) Extract(doc *goquery.Document, opts *ExtractionOpt
 	return strings.TrimSpace(html), nil
 }
 
+// nodeStats holds a subtree's total visible text length (descendant text,
+// excluding script/style/noscript) and descendant element count, used to
+// score candidate content blocks by text-to-tag density.
+type nodeStats struct {
+	textLen  int
+	tagCount int
+}
+
+// computeNodeStats fills stats with every element's nodeStats in a single
+// bottom-up pass over n's subtree.
+func computeNodeStats(n *html.Node, stats map[*html.Node]nodeStats) nodeStats {
+	if n.Type == html.ElementNode {
+		switch n.Data {
+		case "script", "style", "noscript":
+			stats[n] = nodeStats{}
+			return nodeStats{}
+		}
+	}
+
+	var s nodeStats
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case html.TextNode:
+			s.textLen += len(strings.TrimSpace(c.Data))
+		case html.ElementNode:
+			cs := computeNodeStats(c, stats)
+			s.textLen += cs.textLen
+			s.tagCount += cs.tagCount + 1
+		}
+	}
+	stats[n] = s
+	return s
+}
+
+func hasSkippedClassOrID(n *html.Node, patterns []string) bool {
+	var class, id string
+	for _, attr := range n.Attr {
+		switch attr.Key {
+		case "class":
+			class = attr.Val
+		case "id":
+			id = attr.Val
+		}
+	}
+	combined := strings.ToLower(class + " " + id)
+	for _, p := range patterns {
+		if strings.Contains(combined, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// findBestBlockByDensity scores every candidate block by
+// textLen/(1+tagCount), then widens the pick to the highest ancestor whose
+// text is still at least 80% made up of the candidate's own text. That
+// widening is what keeps a single dominant article body from floating all
+// the way out to <body> just because <body> trivially contains it too.
 func findBestBlockByDensity(selection *goquery.Selection, minDensity float64, minSize int) *html.Node {
-	var bestNode *html.Node
-	var bestScore float64
+	if len(selection.Nodes) == 0 {
+		return nil
+	}
+	root := selection.Nodes[0]
+
+	stats := make(map[*html.Node]nodeStats)
+	computeNodeStats(root, stats)
 
 	contentTags := map[string]bool{
 		"div": true, "article": true, "section": true, "main": true,
 		"p": true, "td": true, "blockquote": true,
 	}
+	skipPatterns := []string{"nav", "menu", "sidebar", "footer", "header", "ad", "comment", "share", "social"}
 
-	selection.Find("*").Each(func(i int, s *goquery.Selection) {
-		tagName := goquery.NodeName(s)
-		if !contentTags[tagName] {
-			return
-		}
+	var best *html.Node
+	var bestScore float64
 
-		class, _ := s.Attr("class")
-		id, _ := s.Attr("id")
-		combined := strings.ToLower(class + " " + id)
-		skipPatterns := []string{"nav", "menu", "sidebar", "footer", "header", "ad", "comment", "share", "social"}
-		for _, pattern := range skipPatterns {
-			if strings.Contains(combined, pattern) {
-				return
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && contentTags[n.Data] && !hasSkippedClassOrID(n, skipPatterns) {
+			st := stats[n]
+			if st.textLen >= minSize {
+				score := float64(st.textLen) / float64(1+st.tagCount)
+				if score >= minDensity && score > bestScore {
+					bestScore = score
+					best = n
+				}
 			}
 		}
-
-		text := strings.TrimSpace(s.Text())
-		if len(text) < minSize {
-			return
-		}
-
-		htmlContent, _ := s.Html()
-		if len(htmlContent) == 0 {
-			return
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode {
+				walk(c)
+			}
 		}
+	}
+	walk(root)
 
-		density := float64(len(text)) / float64(len(htmlContent))
+	if best == nil {
+		return nil
+	}
 
-		score := density
-		if len(text) > 500 {
-			score *= 1.2
+	bestTextLen := stats[best].textLen
+	for {
+		parent := best.Parent
+		if parent == nil {
+			break
 		}
-		if len(text) > 1000 {
-			score *= 1.3
-		}
-
-		if s.Find("p").Length() > 2 {
-			score *= 1.5
+		parentStats, ok := stats[parent]
+		if !ok || parentStats.textLen == 0 {
+			break
 		}
-
-		if score > bestScore && density >= minDensity {
-			bestScore = score
-			bestNode = s.Nodes[0]
+		if float64(bestTextLen)/float64(parentStats.textLen) < 0.8 {
+			break
 		}
-	})
+		best = parent
+	}
 
-	return bestNode
+	return best
 }
 
 // XPathRegexStrategy extracts content using regex patterns on raw HTML
@@ -197,6 +258,42 @@ func (s *DOMPositionStrategy) Name() string {
 }
 
 func (s *DOMPositionStrategy) Extract(doc *goquery.Document, opts *ExtractionOptions) (string, error) {
+	removePatterns := []string{
+		"nav", "aside", "header", "footer",
+		".ads", ".ad", ".advertisement",
+		".sidebar", ".side-bar",
+		".comments", ".comment-section",
+		".share", ".social", ".sharing",
+		".related", ".recommended",
+		".navigation", ".breadcrumb",
+		".author-bio", ".author-info",
+		"script", "style", "noscript",
+	}
+
+	if body := doc.Find("body"); body.Length() > 0 {
+		minWidth := s.MinWidth
+		if minWidth <= 0 {
+			minWidth = 300
+		}
+		maxDepth := s.MaxDepth
+		if maxDepth <= 0 {
+			maxDepth = 10
+		}
+
+		if node := findDeepestByWidth(body.Nodes[0], minWidth, maxDepth); node != nil {
+			content := goquery.NewDocumentFromNode(node).Selection.Clone()
+			for _, removePattern := range removePatterns {
+				content.Find(removePattern).Remove()
+			}
+			if htmlContent, err := content.Html(); err == nil {
+				htmlContent = strings.TrimSpace(htmlContent)
+				if len(htmlContent) > 100 {
+					return htmlContent, nil
+				}
+			}
+		}
+	}
+
 	patterns := []string{
 		"article.content",
 		"article.post-content",
@@ -228,18 +325,6 @@ func (s *DOMPositionStrategy) Extract(doc *goquery.Document, opts *ExtractionOpt
 		"div.main",
 	}
 
-	removePatterns := []string{
-		"nav", "aside", "header", "footer",
-		".ads", ".ad", ".advertisement",
-		".sidebar", ".side-bar",
-		".comments", ".comment-section",
-		".share", ".social", ".sharing",
-		".related", ".recommended",
-		".navigation", ".breadcrumb",
-		".author-bio", ".author-info",
-		"script", "style", "noscript",
-	}
-
 	for _, pattern := range patterns {
 		content := doc.Find(pattern)
 		if content.Length() == 0 {
@@ -266,6 +351,49 @@ func (s *DOMPositionStrategy) Extract(doc *goquery.Document, opts *ExtractionOpt
 	return "", fmt.Errorf("no content found using DOM position heuristics")
 }
 
+// findDeepestByWidth walks root looking for the deepest block-level element
+// whose longest text line is at least minWidth characters - a proxy for "is
+// this a real paragraph" versus the short, line-per-item text typical of
+// navigation and sidebars. Search stops descending past maxDepth.
+func findDeepestByWidth(root *html.Node, minWidth, maxDepth int) *html.Node {
+	var best *html.Node
+	bestDepth := -1
+
+	blockTags := map[string]bool{"p": true, "div": true, "article": true, "section": true, "main": true}
+
+	var walk func(n *html.Node, depth int)
+	walk = func(n *html.Node, depth int) {
+		if depth > maxDepth {
+			return
+		}
+		if n.Type == html.ElementNode && blockTags[n.Data] {
+			text := goquery.NewDocumentFromNode(n).Text()
+			if longestLine(text) >= minWidth && depth > bestDepth {
+				bestDepth = depth
+				best = n
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode {
+				walk(c, depth+1)
+			}
+		}
+	}
+	walk(root, 0)
+
+	return best
+}
+
+func longestLine(text string) int {
+	longest := 0
+	for _, line := range strings.Split(text, "\n") {
+		if l := len(strings.TrimSpace(line)); l > longest {
+			longest = l
+		}
+	}
+	return longest
+}
+
 // HybridStrategy tries multiple strategies in sequence until one succeeds
 type HybridStrategy struct {
 	Strategies []DetectionStrategy
@@ -307,6 +435,7 @@ func NewHybridStrategy(cssSelector string, excludeSelectors []string, textDensit
 	}
 
 	strategies = append(strategies, &textDensity)
+	strategies = append(strategies, &ReadabilityStrategy{})
 	strategies = append(strategies, &DOMPositionStrategy{})
 
 	return &HybridStrategy{Strategies: strategies}
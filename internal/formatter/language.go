@@ -0,0 +1,168 @@
+package formatter
+
+import (
+	"embed"
+	"sort"
+	"strings"
+)
+
+// trigramProfileSize is both how many of a language's most common trigrams
+// we keep in trigramProfiles and the out-of-place penalty charged when a
+// text's trigram doesn't appear in a profile at all - the standard choice
+// from Cavnar & Trenkle's N-Gram text categorization approach. The paper
+// itself uses profiles up to several hundred trigrams deep; 100 is a
+// deliberately smaller cut, since the representative samples backing
+// langdata/*.trigrams are paragraph-length, not a multi-thousand-word
+// corpus, and ranking much past 100 on that little source text would mean
+// ranking noise rather than real frequency.
+const trigramProfileSize = 100
+
+//go:embed langdata/*.trigrams
+var trigramDataFS embed.FS
+
+// trigramLanguages fixes iteration order over trigramProfiles so ties in
+// outOfPlaceDistance resolve deterministically rather than depending on Go's
+// randomized map iteration order.
+var trigramLanguages = []string{"en", "es", "fr", "de", "it", "pt", "nl", "ru", "zh", "ja", "ko"}
+
+// trigramProfiles lists each language's most frequent trigrams, most common
+// first, loaded from langdata/*.trigrams (one trigram per line, generated by
+// cmd/langgen from representative text samples - see that command for the
+// source paragraphs and regeneration instructions). Word boundaries are
+// stored as `_` rather than a literal space so the data files are readable
+// and diff cleanly; loadTrigramProfile converts them back to the actual
+// space character trigramsOf produces, since a trailing/leading space is
+// what lets profiles score "the ", " th", etc.
+var trigramProfiles = mustLoadTrigramProfiles()
+
+func mustLoadTrigramProfiles() map[string][]string {
+	profiles := make(map[string][]string, len(trigramLanguages))
+	for _, lang := range trigramLanguages {
+		profile, err := loadTrigramProfile(lang)
+		if err != nil {
+			panic("formatter: " + err.Error())
+		}
+		profiles[lang] = profile
+	}
+	return profiles
+}
+
+func loadTrigramProfile(lang string) ([]string, error) {
+	data, err := trigramDataFS.ReadFile("langdata/" + lang + ".trigrams")
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	profile := make([]string, len(lines))
+	for i, line := range lines {
+		profile[i] = strings.ReplaceAll(line, "_", " ")
+	}
+	return profile, nil
+}
+
+// trigramsOf splits text into overlapping 3-rune windows, lowercased, with
+// runs of whitespace collapsed to a single space so "a  b" and "a b" produce
+// the same trigrams.
+func trigramsOf(text string) []string {
+	text = strings.ToLower(text)
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(" " + text + " ")
+
+	if len(runes) < 3 {
+		return nil
+	}
+
+	grams := make([]string, 0, len(runes)-2)
+	for i := 0; i+3 <= len(runes); i++ {
+		grams = append(grams, string(runes[i:i+3]))
+	}
+	return grams
+}
+
+// rankedProfile returns text's trigrams ranked by descending frequency
+// (ties broken by first occurrence, for determinism), capped to
+// trigramProfileSize entries - the same shape as trigramProfiles.
+func rankedProfile(text string) []string {
+	counts := make(map[string]int)
+	var order []string
+	for _, g := range trigramsOf(text) {
+		if counts[g] == 0 {
+			order = append(order, g)
+		}
+		counts[g]++
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return counts[order[i]] > counts[order[j]]
+	})
+
+	if len(order) > trigramProfileSize {
+		order = order[:trigramProfileSize]
+	}
+	return order
+}
+
+// outOfPlaceDistance scores how different two ranked trigram profiles are:
+// for each trigram in `input`, add the absolute difference between its rank
+// in input and its rank in profile, or trigramProfileSize if profile
+// doesn't contain it at all. Lower is more similar.
+func outOfPlaceDistance(input, profile []string) int {
+	rank := make(map[string]int, len(profile))
+	for i, g := range profile {
+		rank[g] = i
+	}
+
+	distance := 0
+	for i, g := range input {
+		if r, ok := rank[g]; ok {
+			d := r - i
+			if d < 0 {
+				d = -d
+			}
+			distance += d
+		} else {
+			distance += trigramProfileSize
+		}
+	}
+	return distance
+}
+
+// trigramMaxConfidentAvgDistance is the ceiling, in the same units as
+// trigramProfileSize (the per-trigram penalty when a trigram is absent from
+// a profile entirely), on the best match's average per-trigram distance
+// before DetectLanguage gives up and returns "und" instead of guessing. A
+// text that resembles none of the known profiles (too short, transliterated,
+// or a language with no profile at all) scores close to trigramProfileSize
+// against every language, so the ceiling is set comfortably below that.
+const trigramMaxConfidentAvgDistance = trigramProfileSize - 3
+
+// DetectLanguage identifies the dominant language of text using Cavnar &
+// Trenkle's N-gram text categorization: text is ranked into a trigram
+// frequency profile and matched against each known language's reference
+// profile by out-of-place distance, returning the closest match's ISO 639-1
+// code. Returns "und" (the ISO 639-2 "undetermined" code) when text is too
+// short to profile, or its best match isn't close enough to be confident,
+// rather than defaulting to "en".
+func DetectLanguage(text string) string {
+	input := rankedProfile(ExtractTextContent(text))
+	if len(input) == 0 {
+		return "und"
+	}
+
+	best := "und"
+	bestDistance := -1
+	for _, lang := range trigramLanguages {
+		d := outOfPlaceDistance(input, trigramProfiles[lang])
+		if bestDistance == -1 || d < bestDistance {
+			bestDistance = d
+			best = lang
+		}
+	}
+
+	avgDistance := float64(bestDistance) / float64(len(input))
+	if avgDistance > trigramMaxConfidentAvgDistance {
+		return "und"
+	}
+	return best
+}
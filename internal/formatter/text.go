@@ -46,16 +46,16 @@ func trimLines(text string) string {
 
 func convertSmartQuotes(text string) string {
 	re := regexp.MustCompile(`(^|[\s\n\r\(])\"`)
-	text = re.ReplaceAllString(text, "$1"")
+	text = re.ReplaceAllString(text, "$1“")
 
 	re = regexp.MustCompile(`\"([\s\n\r\.,!?\)\:]|$)`)
-	text = re.ReplaceAllString(text, ""$1")
+	text = re.ReplaceAllString(text, "”$1")
 
 	re = regexp.MustCompile(`(^|[\s\n\r\(])\'`)
-	text = re.ReplaceAllString(text, "$1'")
+	text = re.ReplaceAllString(text, "$1‘")
 
 	re = regexp.MustCompile(`\'([\s\n\r\.,!?\)\:]|$)`)
-	text = re.ReplaceAllString(text, "'$1")
+	text = re.ReplaceAllString(text, "’$1")
 
 	re = regexp.MustCompile(`([a-zA-Z])\'([a-zA-Z])`)
 	text = re.ReplaceAllString(text, "$1'$2")
@@ -166,43 +166,3 @@ func CharacterCount(text string) int {
 	text = ExtractTextContent(text)
 	return len(text)
 }
-
-// DetectLanguage attempts to detect the primary language (simplified)
-func DetectLanguage(text string) string {
-	text = strings.ToLower(text)
-
-	englishWords := []string{"the", "and", "is", "it", "to", "of", "in", "that", "was", "for"}
-	spanishWords := []string{"el", "la", "de", "que", "y", "en", "un", "es", "por", "con"}
-	frenchWords := []string{"le", "la", "de", "et", "est", "un", "une", "que", "dans", "pour"}
-
-	englishCount := 0
-	spanishCount := 0
-	frenchCount := 0
-
-	words := strings.Fields(text)
-	for _, word := range words {
-		for _, en := range englishWords {
-			if word == en {
-				englishCount++
-			}
-		}
-		for _, es := range spanishWords {
-			if word == es {
-				spanishCount++
-			}
-		}
-		for _, fr := range frenchWords {
-			if word == fr {
-				frenchCount++
-			}
-		}
-	}
-
-	if spanishCount > englishCount && spanishCount > frenchCount {
-		return "es"
-	}
-	if frenchCount > englishCount && frenchCount > spanishCount {
-		return "fr"
-	}
-	return "en"
-}
@@ -0,0 +1,152 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// voidElements are the HTML elements that must be self-closed in XHTML
+// (no children, no separate closing tag).
+var voidElements = map[string]bool{
+	"br": true, "hr": true, "img": true, "input": true, "meta": true,
+	"link": true, "area": true, "base": true, "col": true, "embed": true,
+	"param": true, "source": true, "track": true, "wbr": true,
+}
+
+// droppedEntirely are elements whose content must never reach the reader as
+// visible text - unlike an unrecognized presentational tag, unwrapping these
+// would leak their raw (script, CSS, or inert-markup) contents as prose.
+var droppedEntirely = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true,
+}
+
+// escapeTable is the shared escaping used for both text nodes and attribute
+// values - XHTML requires bare "&"/"<"/">" to always be escaped, and "&#160;"
+// is the portable way to write a non-breaking space without relying on an
+// HTML entity declaration an XML parser wouldn't know about.
+var escapeTable = []struct {
+	from string
+	to   string
+}{
+	{"&", "&amp;"},
+	{"<", "&lt;"},
+	{">", "&gt;"},
+	{"\"", "&quot;"},
+	{" ", "&#160;"},
+}
+
+func escapeXHTML(s string) string {
+	for _, e := range escapeTable {
+		s = strings.ReplaceAll(s, e.from, e.to)
+	}
+	return s
+}
+
+// stripIllegalCodepoints removes C0 control characters that aren't legal in
+// XML 1.0 (everything below 0x20 except tab, newline, and carriage return),
+// which EPUBCheck's strict XHTML parse rejects outright.
+func stripIllegalCodepoints(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 && r != '\t' && r != '\n' && r != '\r' {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// XHTMLSerializer parses HTML, drops any element not in AllowedTags (its
+// children are kept and re-parented), and serializes the result as
+// well-formed XHTML: escaped text/attributes, stripped illegal codepoints,
+// and self-closed void elements.
+type XHTMLSerializer struct {
+	AllowedTags map[string]bool
+}
+
+// NewXHTMLSerializer creates a serializer using HTMLNormalizer's default
+// allowed tag set.
+func NewXHTMLSerializer() *XHTMLSerializer {
+	return &XHTMLSerializer{AllowedTags: NewHTMLNormalizer().AllowedTags}
+}
+
+// Serialize parses htmlContent as an HTML fragment and renders it as XHTML.
+func (s *XHTMLSerializer) Serialize(htmlContent string) (string, error) {
+	nodes, err := html.ParseFragment(strings.NewReader(htmlContent), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	allowed := s.AllowedTags
+	if allowed == nil {
+		allowed = NewHTMLNormalizer().AllowedTags
+	}
+
+	var b strings.Builder
+	for _, n := range nodes {
+		writeXHTMLNode(&b, n, allowed)
+	}
+
+	return strings.TrimSpace(b.String()), nil
+}
+
+func writeXHTMLNode(b *strings.Builder, n *html.Node, allowed map[string]bool) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(escapeXHTML(stripIllegalCodepoints(n.Data)))
+		return
+	case html.CommentNode, html.DoctypeNode:
+		return
+	case html.ElementNode:
+		// fall through below
+	default:
+		writeXHTMLChildren(b, n, allowed)
+		return
+	}
+
+	if droppedEntirely[n.Data] {
+		return
+	}
+
+	keep := allowed[n.Data]
+	if !keep {
+		writeXHTMLChildren(b, n, allowed)
+		return
+	}
+
+	b.WriteByte('<')
+	b.WriteString(n.Data)
+	for _, attr := range n.Attr {
+		fmt.Fprintf(b, ` %s="%s"`, attr.Key, escapeXHTML(stripIllegalCodepoints(attr.Val)))
+	}
+
+	if voidElements[n.Data] {
+		b.WriteString("/>")
+		return
+	}
+	b.WriteByte('>')
+
+	writeXHTMLChildren(b, n, allowed)
+
+	b.WriteString("</")
+	b.WriteString(n.Data)
+	b.WriteByte('>')
+}
+
+func writeXHTMLChildren(b *strings.Builder, n *html.Node, allowed map[string]bool) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeXHTMLNode(b, c, allowed)
+	}
+}
+
+// ToXHTML is the package-level entry point used as the final step of the
+// output pipeline: clean content in, well-formed XHTML ready for EPUBCheck
+// out.
+func ToXHTML(htmlContent string) (string, error) {
+	return NewXHTMLSerializer().Serialize(htmlContent)
+}
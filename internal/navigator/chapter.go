@@ -41,6 +41,10 @@ func (cn *ChapterNavigator) DiscoverChapters(startURL string) ([]ChapterInfo, er
 		return cn.discoverByNextLink(startURL)
 	case "toc":
 		return cn.discoverByTOC()
+	case "feed":
+		return cn.discoverByFeed()
+	case "sitemap":
+		return cn.discoverBySitemap()
 	default:
 		return nil, fmt.Errorf("unknown navigation method: %s", cn.config.Method)
 	}
@@ -93,6 +97,14 @@ func (cn *ChapterNavigator) discoverByTOC() ([]ChapterInfo, error) {
 	return nil, fmt.Errorf("TOC discovery requires scraper to fetch TOC page first")
 }
 
+func (cn *ChapterNavigator) discoverByFeed() ([]ChapterInfo, error) {
+	return nil, fmt.Errorf("feed discovery requires scraper to fetch the feed first")
+}
+
+func (cn *ChapterNavigator) discoverBySitemap() ([]ChapterInfo, error) {
+	return nil, fmt.Errorf("sitemap discovery requires scraper to fetch the sitemap first")
+}
+
 // ParseTOCPage parses a table of contents page and extracts chapter links
 func (cn *ChapterNavigator) ParseTOCPage(doc *goquery.Document, baseURL string) ([]ChapterInfo, error) {
 	if cn.config.TOCLinkSelector == "" {
@@ -0,0 +1,166 @@
+package navigator
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// rssFeed matches the subset of RSS 2.0 we care about: item title, link and pubDate.
+type rssFeed struct {
+	Channel struct {
+		Items []struct {
+			Title   string `xml:"title"`
+			Link    string `xml:"link"`
+			PubDate string `xml:"pubDate"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+// atomLink is an Atom <link> element: the href is an attribute, and an
+// entry may list more than one (alternate, self, ...) distinguished by rel.
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+// atomFeed matches the subset of Atom we care about.
+type atomFeed struct {
+	Entries []struct {
+		Title   string     `xml:"title"`
+		Links   []atomLink `xml:"link"`
+		Updated string     `xml:"updated"`
+	} `xml:"entry"`
+}
+
+// feedDateLayouts are the datetime forms ParseFeed tries against RSS's
+// pubDate (nominally RFC822/RFC1123Z) and Atom's updated (RFC3339), in
+// roughly most-to-least specific order - feeds are inconsistent enough in
+// practice that it's worth trying both.
+var feedDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+func parseFeedDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}
+	}
+	for _, layout := range feedDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func atomEntryLink(links []atomLink) string {
+	for _, l := range links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			return l.Href
+		}
+	}
+	if len(links) > 0 {
+		return links[0].Href
+	}
+	return ""
+}
+
+// ParseFeed parses an RSS 2.0 or Atom feed document into chapter links. By
+// default (cn.config.FeedOrder == "" or "asc") entries are returned in
+// reading order (oldest first) since feeds are normally published newest
+// first; FeedOrder "desc" keeps the feed's own newest-first order instead.
+// cn.config.FeedSince, if set, drops entries published/updated before that
+// cutoff - entries with no parseable date are kept regardless. MaxChapters,
+// if set, caps how many entries are returned.
+func (cn *ChapterNavigator) ParseFeed(data []byte) ([]ChapterInfo, error) {
+	type entry struct {
+		title     string
+		link      string
+		published time.Time
+	}
+
+	var entries []entry
+
+	var rss rssFeed
+	if err := xml.Unmarshal(data, &rss); err == nil && len(rss.Channel.Items) > 0 {
+		for _, item := range rss.Channel.Items {
+			entries = append(entries, entry{
+				title:     strings.TrimSpace(item.Title),
+				link:      strings.TrimSpace(item.Link),
+				published: parseFeedDate(item.PubDate),
+			})
+		}
+	} else {
+		var atom atomFeed
+		if err := xml.Unmarshal(data, &atom); err != nil {
+			return nil, fmt.Errorf("failed to parse feed as RSS or Atom: %w", err)
+		}
+		for _, e := range atom.Entries {
+			entries = append(entries, entry{
+				title:     strings.TrimSpace(e.Title),
+				link:      strings.TrimSpace(atomEntryLink(e.Links)),
+				published: parseFeedDate(e.Updated),
+			})
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("feed contained no items or entries")
+	}
+
+	if cn.config.FeedOrder != "desc" {
+		for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+			entries[i], entries[j] = entries[j], entries[i]
+		}
+	}
+
+	var since time.Time
+	if cn.config.FeedSince != "" {
+		since = parseFeedDate(cn.config.FeedSince)
+		if since.IsZero() {
+			return nil, fmt.Errorf("invalid feedSince: %q", cn.config.FeedSince)
+		}
+	}
+
+	chapters := make([]ChapterInfo, 0, len(entries))
+	index := 1
+	for _, e := range entries {
+		if e.link == "" || cn.visited[e.link] {
+			continue
+		}
+		if !since.IsZero() && !e.published.IsZero() && e.published.Before(since) {
+			continue
+		}
+		cn.visited[e.link] = true
+
+		if cn.config.MaxChapters > 0 && index > cn.config.MaxChapters {
+			break
+		}
+
+		title := e.title
+		if title == "" {
+			title = fmt.Sprintf("Chapter %d", index)
+		}
+
+		chapters = append(chapters, ChapterInfo{
+			URL:    e.link,
+			Title:  title,
+			Index:  index,
+			Status: "pending",
+		})
+		index++
+	}
+
+	if len(chapters) == 0 {
+		return nil, fmt.Errorf("feed contained no matching entries")
+	}
+
+	cn.chapters = chapters
+	return chapters, nil
+}
@@ -0,0 +1,158 @@
+package navigator
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sitemapURLSet matches the <urlset> form of the sitemap.xml protocol -
+// <loc> and <lastmod>; <priority>/<changefreq> aren't useful for chapter
+// discovery and are ignored.
+type sitemapURLSet struct {
+	URLs []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+	} `xml:"url"`
+}
+
+// sitemapIndexDoc matches the <sitemapindex> form, used by large sites to
+// split their sitemap across multiple files.
+type sitemapIndexDoc struct {
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+}
+
+// SitemapURLEntry is one <url> entry from a sitemap document.
+type SitemapURLEntry struct {
+	Loc string
+	// LastMod is the zero Time if the entry had no <lastmod>, or it didn't
+	// parse as a W3C datetime or plain date.
+	LastMod time.Time
+}
+
+// ParseSitemapDocument parses one sitemap.xml document. A plain <urlset>
+// document yields its <url> entries; a <sitemapindex> document - the common
+// case on the large archive/catalog sites this tool targets, where the
+// sitemap is split across multiple files - yields the child sitemap URLs
+// the caller should fetch and parse next instead.
+func ParseSitemapDocument(data []byte) (entries []SitemapURLEntry, childSitemaps []string, err error) {
+	var index sitemapIndexDoc
+	if err := xml.Unmarshal(data, &index); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse sitemap: %w", err)
+	}
+	if len(index.Sitemaps) > 0 {
+		for _, sm := range index.Sitemaps {
+			if loc := strings.TrimSpace(sm.Loc); loc != "" {
+				childSitemaps = append(childSitemaps, loc)
+			}
+		}
+		return nil, childSitemaps, nil
+	}
+
+	var set sitemapURLSet
+	if err := xml.Unmarshal(data, &set); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse sitemap: %w", err)
+	}
+	for _, u := range set.URLs {
+		loc := strings.TrimSpace(u.Loc)
+		if loc == "" {
+			continue
+		}
+		entries = append(entries, SitemapURLEntry{Loc: loc, LastMod: parseSitemapLastMod(u.LastMod)})
+	}
+	return entries, nil, nil
+}
+
+// sitemapLastModLayouts are the datetime forms <lastmod> is allowed to use
+// per the sitemaps.org protocol (a W3C datetime, with or without a time/zone
+// component), in roughly most-to-least specific order.
+var sitemapLastModLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+func parseSitemapLastMod(s string) time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}
+	}
+	for _, layout := range sitemapLastModLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// ParseSitemap converts parsed sitemap URL entries (gathered from one or
+// more documents by ParseSitemapDocument) into chapter links. Entries are
+// sorted oldest-<lastmod>-first, the same "read in publish order"
+// convention ParseFeed uses for RSS/Atom; entries missing a <lastmod> keep
+// their original relative position. If cn.config.SitemapURLFilter is set,
+// only <loc> entries matching it are kept - sitemaps are frequently shared
+// with non-chapter pages (tag indexes, the homepage, ...).
+func (cn *ChapterNavigator) ParseSitemap(entries []SitemapURLEntry) ([]ChapterInfo, error) {
+	var filter *regexp.Regexp
+	if cn.config.SitemapURLFilter != "" {
+		re, err := regexp.Compile(cn.config.SitemapURLFilter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sitemapUrlFilter: %w", err)
+		}
+		filter = re
+	}
+
+	sorted := make([]SitemapURLEntry, len(entries))
+	copy(sorted, entries)
+	var datedSlots []int
+	var dated []SitemapURLEntry
+	for i, e := range sorted {
+		if !e.LastMod.IsZero() {
+			datedSlots = append(datedSlots, i)
+			dated = append(dated, e)
+		}
+	}
+	sort.SliceStable(dated, func(i, j int) bool {
+		return dated[i].LastMod.Before(dated[j].LastMod)
+	})
+	for k, slot := range datedSlots {
+		sorted[slot] = dated[k]
+	}
+
+	chapters := make([]ChapterInfo, 0, len(sorted))
+	index := 1
+	for _, e := range sorted {
+		loc := strings.TrimSpace(e.Loc)
+		if loc == "" || cn.visited[loc] {
+			continue
+		}
+		if filter != nil && !filter.MatchString(loc) {
+			continue
+		}
+		cn.visited[loc] = true
+
+		if cn.config.MaxChapters > 0 && index > cn.config.MaxChapters {
+			break
+		}
+
+		chapters = append(chapters, ChapterInfo{
+			URL:    loc,
+			Title:  fmt.Sprintf("Chapter %d", index),
+			Index:  index,
+			Status: "pending",
+		})
+		index++
+	}
+
+	if len(chapters) == 0 {
+		return nil, fmt.Errorf("sitemap contained no matching URLs")
+	}
+
+	cn.chapters = chapters
+	return chapters, nil
+}
@@ -0,0 +1,386 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+
+	"web-to-epub-go/internal/config"
+	"web-to-epub-go/internal/navigator"
+)
+
+// coverMinDimension is the smallest width/height, in pixels, an image must
+// have (after resizing) to be considered as an auto-selected book cover.
+const coverMinDimension = 300
+
+// coverMaxDimension is the longest edge, in pixels, an explicitly configured
+// book cover is scaled down to.
+const coverMaxDimension = 1600
+
+// Fetcher fetches the raw bytes of a URL. scraper.Requester satisfies this
+// interface, which lets AssetManager reuse its politeness delay and
+// User-Agent instead of issuing its own unthrottled requests.
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) ([]byte, error)
+}
+
+// Asset is a single downloaded image, deduplicated and re-encoded for
+// embedding in an EPUB.
+type Asset struct {
+	Filename string // unique within the book, e.g. "3a1f9c2b8e7d1a4f.jpg"
+	MimeType string
+	Data     []byte
+	Width    int
+	Height   int
+}
+
+// AssetManager downloads images referenced by chapter HTML, deduplicates
+// them by content hash, re-encodes them to a size-limited JPEG/PNG, and
+// rewrites <img> src attributes to local "images/<filename>" paths so the
+// generated book no longer depends on network access to render.
+type AssetManager struct {
+	fetch Fetcher
+	cfg   config.AssetsConfig
+
+	byURL   map[string]*Asset
+	byHash  map[string]*Asset
+	ordered []*Asset
+}
+
+// NewAssetManager creates an AssetManager that fetches images through fetch.
+// Zero-valued fields in cfg fall back to the same defaults DefaultConfig uses.
+func NewAssetManager(fetch Fetcher, cfg config.AssetsConfig) *AssetManager {
+	if cfg.MaxDimension <= 0 {
+		cfg.MaxDimension = 1200
+	}
+	if cfg.MaxSizeBytes <= 0 {
+		cfg.MaxSizeBytes = 2 * 1024 * 1024
+	}
+	if len(cfg.AllowedMimeTypes) == 0 {
+		cfg.AllowedMimeTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp", "image/svg+xml"}
+	}
+	if len(cfg.LazyLoadAttrs) == 0 {
+		cfg.LazyLoadAttrs = []string{"data-src", "data-original", "data-lazy-src"}
+	}
+
+	return &AssetManager{
+		fetch:  fetch,
+		cfg:    cfg,
+		byURL:  make(map[string]*Asset),
+		byHash: make(map[string]*Asset),
+	}
+}
+
+// Process walks each chapter's HTML, downloads and embeds referenced
+// images, and returns chapters with their img src rewritten to local
+// "images/<filename>" paths. Images that fail to download, decode, or stay
+// within the configured limits are left pointing at their original remote
+// URL rather than failing the whole chapter.
+func (m *AssetManager) Process(ctx context.Context, chapters []Chapter) []Chapter {
+	out := make([]Chapter, len(chapters))
+	for i, ch := range chapters {
+		out[i] = ch
+		out[i].Content = m.processContent(ctx, ch.Content, ch.URL)
+	}
+	return out
+}
+
+// Assets returns every unique image collected so far, in the order each was
+// first encountered.
+func (m *AssetManager) Assets() []*Asset {
+	return m.ordered
+}
+
+// Cover returns the first collected image large enough to plausibly serve
+// as a book cover, or nil if none qualify.
+func (m *AssetManager) Cover() *Asset {
+	for _, a := range m.ordered {
+		if a.Width >= coverMinDimension && a.Height >= coverMinDimension {
+			return a
+		}
+	}
+	return nil
+}
+
+func (m *AssetManager) processContent(ctx context.Context, content, baseURL string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return content
+	}
+
+	body := doc.Find("body")
+	body.Find("img").Each(func(_ int, s *goquery.Selection) {
+		src := m.imageSource(s)
+		if src == "" {
+			return
+		}
+
+		resolved := navigator.ResolveRelativeURL(baseURL, src)
+		asset, err := m.fetchAsset(ctx, resolved)
+		if err != nil {
+			fmt.Printf("⚠ Warning: skipping image %s: %v\n", resolved, err)
+			return
+		}
+
+		s.SetAttr("src", "images/"+asset.Filename)
+		for _, attr := range m.cfg.LazyLoadAttrs {
+			s.RemoveAttr(attr)
+		}
+	})
+
+	rewritten, err := body.Html()
+	if err != nil {
+		return content
+	}
+	return rewritten
+}
+
+// imageSource picks the real image URL off an <img>, preferring any
+// configured lazy-load attribute over src since sites that lazy-load
+// images commonly leave a placeholder spinner/blank gif in src itself.
+func (m *AssetManager) imageSource(s *goquery.Selection) string {
+	for _, attr := range m.cfg.LazyLoadAttrs {
+		if v, ok := s.Attr(attr); ok && strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+	if v, ok := s.Attr("src"); ok {
+		return strings.TrimSpace(v)
+	}
+	return ""
+}
+
+func (m *AssetManager) fetchAsset(ctx context.Context, rawURL string) (*Asset, error) {
+	if asset, ok := m.byURL[rawURL]; ok {
+		return asset, nil
+	}
+
+	data, err := m.fetch.Fetch(ctx, rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch: %w", err)
+	}
+
+	hash := fmt.Sprintf("%x", sha256.Sum256(data))
+	if asset, ok := m.byHash[hash]; ok {
+		m.byURL[rawURL] = asset
+		return asset, nil
+	}
+
+	asset, err := m.encodeAsset(data, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	m.byURL[rawURL] = asset
+	m.byHash[hash] = asset
+	m.ordered = append(m.ordered, asset)
+	return asset, nil
+}
+
+// FetchCover downloads or reads the book's configured cover image - source
+// may be a local file path or an http(s) URL, resolved the same way as
+// in-chapter images - and returns it resized to coverMaxDimension on its
+// longest edge. fit selects "contain" (default, scale to fit) or "cover"
+// (center-crop to a 2:3 portrait before scaling, the conventional e-book
+// cover aspect).
+func (m *AssetManager) FetchCover(ctx context.Context, source, fit string) (*Asset, error) {
+	data, err := m.readCoverSource(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := fmt.Sprintf("%x", sha256.Sum256(data))
+	mimeType := sniffMime(data)
+	if !allowedMime(mimeType, m.cfg.AllowedMimeTypes) {
+		return nil, fmt.Errorf("mime type %s not allowed", mimeType)
+	}
+	if mimeType == "image/svg+xml" {
+		return &Asset{Filename: "cover-" + hash[:16] + ".svg", MimeType: mimeType, Data: data}, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode cover image: %w", err)
+	}
+	if fit == "cover" {
+		img = cropToAspect(img, 2, 3)
+	}
+	img = fitWithinBounds(img, coverMaxDimension)
+
+	encoded, ext, outMime, err := encodeImage(img)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cover image: %w", err)
+	}
+
+	bounds := img.Bounds()
+	return &Asset{
+		Filename: "cover-" + hash[:16] + ext,
+		MimeType: outMime,
+		Data:     encoded,
+		Width:    bounds.Dx(),
+		Height:   bounds.Dy(),
+	}, nil
+}
+
+func (m *AssetManager) readCoverSource(ctx context.Context, source string) ([]byte, error) {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		data, err := m.fetch.Fetch(ctx, source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch cover: %w", err)
+		}
+		return data, nil
+	}
+
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cover file: %w", err)
+	}
+	return data, nil
+}
+
+// cropToAspect center-crops img to the given width:height ratio before
+// scaling, so CoverFit "cover" fills a standard e-book portrait frame
+// instead of being letterboxed.
+func cropToAspect(img image.Image, wRatio, hRatio int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	targetH := w * hRatio / wRatio
+	if targetH <= h {
+		top := b.Min.Y + (h-targetH)/2
+		return subImage(img, image.Rect(b.Min.X, top, b.Max.X, top+targetH))
+	}
+
+	targetW := h * wRatio / hRatio
+	left := b.Min.X + (w-targetW)/2
+	return subImage(img, image.Rect(left, b.Min.Y, left+targetW, b.Max.Y))
+}
+
+func subImage(img image.Image, rect image.Rectangle) image.Image {
+	if si, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return si.SubImage(rect)
+	}
+	dst := image.NewRGBA(rect.Sub(rect.Min))
+	draw.Draw(dst, dst.Bounds(), img, rect.Min, draw.Src)
+	return dst
+}
+
+func (m *AssetManager) encodeAsset(data []byte, hash string) (*Asset, error) {
+	mimeType := sniffMime(data)
+	if !allowedMime(mimeType, m.cfg.AllowedMimeTypes) {
+		return nil, fmt.Errorf("mime type %s not allowed", mimeType)
+	}
+
+	// SVG is a vector XML format image.Decode can't handle; store it as-is
+	// rather than rasterizing, which would need another dependency.
+	if mimeType == "image/svg+xml" {
+		if int64(len(data)) > m.cfg.MaxSizeBytes {
+			return nil, fmt.Errorf("image too large (%d bytes > %d)", len(data), m.cfg.MaxSizeBytes)
+		}
+		return &Asset{Filename: hash[:16] + ".svg", MimeType: mimeType, Data: data}, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	img = fitWithinBounds(img, m.cfg.MaxDimension)
+
+	encoded, ext, outMime, err := encodeImage(img)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	if int64(len(encoded)) > m.cfg.MaxSizeBytes {
+		return nil, fmt.Errorf("image too large after encoding (%d bytes > %d)", len(encoded), m.cfg.MaxSizeBytes)
+	}
+
+	bounds := img.Bounds()
+	return &Asset{
+		Filename: hash[:16] + ext,
+		MimeType: outMime,
+		Data:     encoded,
+		Width:    bounds.Dx(),
+		Height:   bounds.Dy(),
+	}, nil
+}
+
+// sniffMime detects content type from magic bytes, special-casing SVG since
+// http.DetectContentType has no signature for it and reports plain XML/text.
+func sniffMime(data []byte) string {
+	head := data
+	if len(head) > 512 {
+		head = head[:512]
+	}
+	if strings.Contains(strings.ToLower(string(head)), "<svg") {
+		return "image/svg+xml"
+	}
+	return http.DetectContentType(data)
+}
+
+func allowedMime(mimeType string, allowed []string) bool {
+	for _, a := range allowed {
+		if a == mimeType {
+			return true
+		}
+	}
+	return false
+}
+
+// fitWithinBounds scales img down, preserving aspect ratio, so neither
+// dimension exceeds max. Images already within bounds are returned as-is.
+func fitWithinBounds(img image.Image, max int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if max <= 0 || (w <= max && h <= max) {
+		return img
+	}
+
+	scale := float64(max) / float64(w)
+	if h > w {
+		scale = float64(max) / float64(h)
+	}
+	newW, newH := int(float64(w)*scale), int(float64(h)*scale)
+	if newW < 1 {
+		newW = 1
+	}
+	if newH < 1 {
+		newH = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, newW, newH))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Over, nil)
+	return dst
+}
+
+// encodeImage re-encodes img as JPEG, unless it carries transparency, in
+// which case it's kept as PNG so the alpha channel survives.
+func encodeImage(img image.Image) (data []byte, ext string, mimeType string, err error) {
+	if opaque, ok := img.(interface{ Opaque() bool }); ok && !opaque.Opaque() {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", "", err
+		}
+		return buf.Bytes(), ".png", "image/png", nil
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, "", "", err
+	}
+	return buf.Bytes(), ".jpg", "image/jpeg", nil
+}
@@ -0,0 +1,35 @@
+package output
+
+import "fmt"
+
+// EPUBBuilder packages a Book into an EPUB file. GenerateEPUBWithOptions's
+// direct, dependency-free builder (see its doc comment) is the default
+// implementation, factored out behind this interface - and selectable via
+// --epub-backend - so the alternate goEPUBBuilder backend can be swapped in
+// without touching call sites.
+type EPUBBuilder interface {
+	Build(book *Book, outputPath string, opts *EPUBOptions) error
+}
+
+// legacyEPUBBuilder is the default builder: it assembles the EPUB3/EPUB2
+// package directly rather than delegating to a third-party library.
+type legacyEPUBBuilder struct{}
+
+func (legacyEPUBBuilder) Build(book *Book, outputPath string, opts *EPUBOptions) error {
+	return GenerateEPUBWithOptions(book, outputPath, opts)
+}
+
+// NewEPUBBuilder resolves an EPUBBuilder by name for --epub-backend:
+// "legacy" (the default) or "go-epub", which packages the book with
+// github.com/bmaupin/go-epub instead. Any other name is rejected rather than
+// silently falling back to "legacy".
+func NewEPUBBuilder(name string) (EPUBBuilder, error) {
+	switch name {
+	case "", "legacy":
+		return legacyEPUBBuilder{}, nil
+	case "go-epub":
+		return goEPUBBuilder{}, nil
+	default:
+		return nil, fmt.Errorf("unknown EPUB backend %q (valid: %q, %q)", name, "legacy", "go-epub")
+	}
+}
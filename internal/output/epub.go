@@ -1,21 +1,52 @@
 package output
 
 import (
+	"archive/zip"
+	"bytes"
 	"fmt"
 	"html"
+	"image"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
-	"github.com/go-shiori/go-epub"
+	"web-to-epub-go/internal/formatter"
 )
 
+// containerXML is the fixed META-INF/container.xml every EPUB needs to point
+// readers at the OPF package document.
+const containerXML = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
 // EPUBOptions contains options for EPUB generation
 type EPUBOptions struct {
 	IncludeTableOfContents bool
 	CustomCSS              string
 	EmbedFonts             bool
+	// EPUBVersion selects which navigation document(s) to emit: "epub3"
+	// (nav.xhtml only), "epub2" (toc.ncx only), or "both" (nav.xhtml plus a
+	// toc.ncx fallback for EPUB2 readers). Defaults to "both".
+	EPUBVersion string
+	// EmbedImages controls whether book.Images is added to the manifest and
+	// used as the cover. Set false to produce a text-only EPUB even if the
+	// book carries downloaded images, e.g. for a quick preview build.
+	EmbedImages bool
+	// CoverPath overrides book.Cover with a local image file, letting CLI
+	// users pick a cover without going through the scrape config. Unlike
+	// BookConfig.Cover, this is resolved directly from disk, not fetched.
+	CoverPath string
+	// Theme selects a bundled stylesheet override layered on top of the
+	// default CSS: "serif" (the default, a no-op), "sans", "dark", or
+	// "dyslexic". An unrecognized name is treated as "serif".
+	Theme string
 }
 
 // DefaultEPUBOptions returns default EPUB generation options
@@ -23,65 +54,115 @@ func DefaultEPUBOptions() *EPUBOptions {
 	return &EPUBOptions{
 		IncludeTableOfContents: true,
 		EmbedFonts:             false,
+		EPUBVersion:            "both",
+		EmbedImages:            true,
 	}
 }
 
+// epubItem is a single manifest entry - a chapter, image, stylesheet, or
+// navigation document - packaged under OEBPS/.
+type epubItem struct {
+	ID         string
+	Href       string
+	MediaType  string
+	Properties string // e.g. "nav", "cover-image"
+	Data       []byte
+
+	// Title and RefType are only meaningful for chapter items: Title labels
+	// the chapter in the TOC/NCX, and RefType drives landmark nav and NCX
+	// entries ("cover", "toc", "bodymatter", "appendix", ...).
+	Title   string
+	RefType string
+	// Parts is the volume/arc path this item is grouped under (see
+	// Chapter.Parts). A "part" RefType item's own Parts includes itself as
+	// the last element; a chapter's Parts is the group it sits inside.
+	Parts []string
+}
+
 // GenerateEPUB generates an EPUB file from a book
 func GenerateEPUB(book *Book, outputPath string) error {
 	return GenerateEPUBWithOptions(book, outputPath, DefaultEPUBOptions())
 }
 
-// GenerateEPUBWithOptions generates an EPUB file with custom options
+// GenerateEPUBWithOptions builds a standards-compliant EPUB package directly
+// - mimetype, META-INF/container.xml, a content.opf manifest/spine, an
+// EPUB3 nav.xhtml navigation document and/or an EPUB2 toc.ncx fallback,
+// chapter XHTML, CSS, and any embedded images - rather than delegating to a
+// third-party EPUB library.
 func GenerateEPUBWithOptions(book *Book, outputPath string, opts *EPUBOptions) error {
 	if len(book.Chapters) == 0 {
 		return fmt.Errorf("no chapters to include in EPUB")
 	}
 
-	e, err := epub.NewEpub(book.Title)
-	if err != nil {
-		return fmt.Errorf("failed to create EPUB: %w", err)
+	version := opts.EPUBVersion
+	if version == "" {
+		version = "both"
 	}
+	includeNav := version == "epub3" || version == "both"
+	includeNCX := version == "epub2" || version == "both"
 
-	e.SetAuthor(book.Author)
-	if book.Description != "" {
-		e.SetDescription(book.Description)
-	} else {
-		e.SetDescription(fmt.Sprintf("Web novel - %d chapters", len(book.Chapters)))
-	}
-	if book.Lang != "" {
-		e.SetLang(book.Lang)
-	}
-	if book.Identifier != "" {
-		e.SetIdentifier(book.Identifier)
+	if book.Identifier == "" {
+		book.Identifier = GenerateUUID()
 	}
 
-	// Add CSS - AddCSS expects CSS content as string, not file path
 	css := getDefaultCSS()
+	if theme := themeCSS(opts.Theme); theme != "" {
+		css += "\n" + theme
+	}
 	if opts.CustomCSS != "" {
 		css += "\n" + opts.CustomCSS
 	}
-	
-	var cssPath string
-	if css != "" {
-		// The AddCSS function signature: AddCSS(cssContent string, destFilename string) (string, error)
-		cssPath, err = e.AddCSS(css, "styles.css")
+
+	chapters := insertPartPages(buildChapterDocs(book))
+	var imageItems []epubItem
+	var coverID string
+	if opts.EmbedImages {
+		imageItems, coverID = buildImageItems(book)
+	}
+
+	if opts.CoverPath != "" {
+		data, mimeType, err := loadCoverFile(opts.CoverPath)
 		if err != nil {
-			fmt.Printf("⚠ Warning: Failed to add CSS: %v\n", err)
-			cssPath = ""
+			fmt.Printf("⚠ Warning: failed to load cover %s: %v\n", opts.CoverPath, err)
+		} else {
+			book.Cover = data
+			book.CoverType = mimeType
+			if opts.EmbedImages {
+				imageItems, coverID = buildImageItems(book)
+			}
 		}
 	}
 
-	for i, ch := range book.Chapters {
-		cleanContent := sanitizeHTML(ch.Content)
-		cleanContent = normalizeForEPUB(cleanContent)
-
-		sectionBody := formatChapterHTML(ch.Title, cleanContent)
+	if coverID != "" {
+		chapters = append([]epubItem{buildCoverPage(findHref(imageItems, coverID))}, chapters...)
+	}
 
-		_, err := e.AddSection(sectionBody, ch.Title, "", cssPath)
-		if err != nil {
-			fmt.Printf("⚠ Warning: Error adding chapter %d (%s): %v\n", i+1, ch.Title, err)
-		}
+	items := make([]epubItem, 0, len(chapters)+len(imageItems)+3)
+	items = append(items, epubItem{ID: "css", Href: "styles.css", MediaType: "text/css", Data: []byte(css)})
+	items = append(items, imageItems...)
+	items = append(items, chapters...)
+
+	ncxID := ""
+	if includeNav {
+		items = append(items, epubItem{
+			ID:         "nav",
+			Href:       "nav.xhtml",
+			MediaType:  "application/xhtml+xml",
+			Properties: "nav",
+			Data:       buildNav(book, chapters),
+		})
 	}
+	if includeNCX {
+		ncxID = "ncx"
+		items = append(items, epubItem{
+			ID:        ncxID,
+			Href:      "toc.ncx",
+			MediaType: "application/x-dtbncx+xml",
+			Data:      buildNCX(book, chapters),
+		})
+	}
+
+	opf := buildOPF(book, buildManifest(items), buildSpine(chapters, ncxID), coverID)
 
 	if err := os.MkdirAll(outputPath, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
@@ -90,7 +171,7 @@ func GenerateEPUBWithOptions(book *Book, outputPath string, opts *EPUBOptions) e
 	filename := SanitizeFilename(book.Title) + ".epub"
 	fullPath := filepath.Join(outputPath, filename)
 
-	if err := e.Write(fullPath); err != nil {
+	if err := writeEPUBZip(fullPath, opf, items); err != nil {
 		return fmt.Errorf("failed to write EPUB: %w", err)
 	}
 
@@ -104,6 +185,505 @@ func GenerateEPUBWithOptions(book *Book, outputPath string, opts *EPUBOptions) e
 	return nil
 }
 
+// writeEPUBZip packages opf and items into a valid EPUB container at path.
+// The mimetype entry must be first and stored (uncompressed) per the OCF spec.
+func writeEPUBZip(path, opf string, items []epubItem) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+
+	mimeWriter, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err := mimeWriter.Write([]byte("application/epub+zip")); err != nil {
+		return err
+	}
+
+	if err := writeZipFile(zw, "META-INF/container.xml", []byte(containerXML)); err != nil {
+		return err
+	}
+	if err := writeZipFile(zw, "OEBPS/content.opf", []byte(opf)); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := writeZipFile(zw, "OEBPS/"+item.Href, item.Data); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func writeZipFile(zw *zip.Writer, name string, data []byte) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// buildChapterDocs renders each chapter to a standalone XHTML document. An
+// empty ReferenceType is treated as "bodymatter", the common case for
+// scraped chapters.
+func buildChapterDocs(book *Book) []epubItem {
+	items := make([]epubItem, len(book.Chapters))
+	for i, ch := range book.Chapters {
+		cleanContent := sanitizeHTML(ch.Content)
+		xhtmlContent, err := formatter.ToXHTML(cleanContent)
+		if err != nil {
+			fmt.Printf("⚠ Warning: falling back to regex XHTML normalization for %q: %v\n", ch.Title, err)
+			xhtmlContent = normalizeForEPUB(cleanContent)
+		}
+		cleanContent = xhtmlContent
+
+		refType := ch.ReferenceType
+		if refType == "" {
+			refType = "bodymatter"
+		}
+
+		items[i] = epubItem{
+			ID:        fmt.Sprintf("chapter-%04d", i+1),
+			Href:      fmt.Sprintf("chapter-%04d.xhtml", i+1),
+			MediaType: "application/xhtml+xml",
+			Title:     ch.Title,
+			RefType:   refType,
+			Parts:     ch.Parts,
+			Data:      []byte(chapterXHTML(ch.Title, cleanContent, refType)),
+		}
+	}
+	return items
+}
+
+func chapterXHTML(title, content, refType string) string {
+	escapedTitle := html.EscapeString(title)
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<head>
+<title>%s</title>
+<link rel="stylesheet" type="text/css" href="styles.css"/>
+</head>
+<body epub:type="%s">
+<h1 class="chapter-title">%s</h1>
+%s
+</body>
+</html>
+`, escapedTitle, refType, escapedTitle, content)
+}
+
+// insertPartPages scans chapters in reading order and, each time Parts
+// changes, inserts a synthetic section page per newly-entered volume/arc
+// level ahead of the chapter - giving readers a "Volume 1" landing page in
+// the spine and TOC instead of dropping straight into its first chapter.
+func insertPartPages(chapters []epubItem) []epubItem {
+	out := make([]epubItem, 0, len(chapters))
+	var current []string
+	partN := 0
+
+	for _, ch := range chapters {
+		common := 0
+		for common < len(current) && common < len(ch.Parts) && current[common] == ch.Parts[common] {
+			common++
+		}
+		for d := common; d < len(ch.Parts); d++ {
+			partN++
+			out = append(out, buildPartPage(partN, ch.Parts[:d+1]))
+		}
+		current = ch.Parts
+		out = append(out, ch)
+	}
+
+	return out
+}
+
+// buildPartPage renders a standalone section page for one level of a
+// volume/arc hierarchy (e.g. "Volume 1"), referenced from the spine with
+// epub:type="part".
+func buildPartPage(n int, path []string) epubItem {
+	title := path[len(path)-1]
+	doc := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<head>
+<title>%s</title>
+<link rel="stylesheet" type="text/css" href="styles.css"/>
+</head>
+<body epub:type="part">
+<h1 class="part-title">%s</h1>
+</body>
+</html>
+`, html.EscapeString(title), html.EscapeString(title))
+
+	return epubItem{
+		ID:        fmt.Sprintf("part-%04d", n),
+		Href:      fmt.Sprintf("part-%04d.xhtml", n),
+		MediaType: "application/xhtml+xml",
+		Title:     title,
+		RefType:   "part",
+		Parts:     path,
+		Data:      []byte(doc),
+	}
+}
+
+// buildImageItems turns a book's downloaded assets into manifest items.
+// book.Cover is matched against the asset list (AssetManager may have
+// auto-selected it from the same images) so it isn't embedded twice; if it
+// doesn't match anything already present, it's added as a standalone item.
+// Returns the manifest id to mark "cover-image", or "" if there is no cover.
+func buildImageItems(book *Book) ([]epubItem, string) {
+	items := make([]epubItem, 0, len(book.Images)+1)
+	coverID := ""
+
+	for _, img := range book.Images {
+		id := "img-" + strings.TrimSuffix(img.Filename, filepath.Ext(img.Filename))
+		properties := ""
+		if len(book.Cover) > 0 && bytes.Equal(img.Data, book.Cover) {
+			properties = "cover-image"
+			coverID = id
+		}
+		items = append(items, epubItem{
+			ID:         id,
+			Href:       "images/" + img.Filename,
+			MediaType:  img.MimeType,
+			Properties: properties,
+			Data:       img.Data,
+		})
+	}
+
+	if coverID == "" && len(book.Cover) > 0 {
+		coverID = "cover-image"
+		items = append(items, epubItem{
+			ID:         coverID,
+			Href:       "images/cover" + coverExtension(book.CoverType),
+			MediaType:  book.CoverType,
+			Properties: "cover-image",
+			Data:       book.Cover,
+		})
+	}
+
+	return items, coverID
+}
+
+// loadCoverFile reads a local cover image file and validates/resizes it the
+// same way AssetManager.FetchCover does, for the EPUBOptions.CoverPath path
+// where there's no Fetcher available to go through AssetManager itself.
+func loadCoverFile(path string) ([]byte, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read cover file: %w", err)
+	}
+
+	mimeType := sniffMime(data)
+	if mimeType == "image/svg+xml" {
+		return data, mimeType, nil
+	}
+	if !allowedMime(mimeType, []string{"image/jpeg", "image/png", "image/gif", "image/webp"}) {
+		return nil, "", fmt.Errorf("unsupported cover image type %s", mimeType)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode cover image: %w", err)
+	}
+	img = fitWithinBounds(img, coverMaxDimension)
+
+	encoded, _, outMime, err := encodeImage(img)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode cover image: %w", err)
+	}
+	return encoded, outMime, nil
+}
+
+// buildCoverPage renders a standalone first page containing only the cover
+// image, so Kindle and other older readers that ignore the OPF
+// properties="cover-image" hint still show it as the book's first page.
+func buildCoverPage(coverHref string) epubItem {
+	doc := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<head>
+<title>Cover</title>
+<link rel="stylesheet" type="text/css" href="styles.css"/>
+</head>
+<body epub:type="cover">
+<div class="cover"><img src="%s" alt="Cover"/></div>
+</body>
+</html>
+`, coverHref)
+
+	return epubItem{
+		ID:        "cover-page",
+		Href:      "cover.xhtml",
+		MediaType: "application/xhtml+xml",
+		Title:     "Cover",
+		RefType:   "cover",
+		Data:      []byte(doc),
+	}
+}
+
+// findHref returns the Href of the item with the given ID, or "".
+func findHref(items []epubItem, id string) string {
+	for _, item := range items {
+		if item.ID == id {
+			return item.Href
+		}
+	}
+	return ""
+}
+
+func coverExtension(mimeType string) string {
+	if mimeType == "image/png" {
+		return ".png"
+	}
+	return ".jpg"
+}
+
+// buildManifest renders the OPF <manifest> element listing every packaged item.
+func buildManifest(items []epubItem) string {
+	var b strings.Builder
+	b.WriteString("  <manifest>\n")
+	for _, item := range items {
+		properties := ""
+		if item.Properties != "" {
+			properties = fmt.Sprintf(` properties="%s"`, item.Properties)
+		}
+		fmt.Fprintf(&b, `    <item id="%s" href="%s" media-type="%s"%s/>`+"\n", item.ID, item.Href, item.MediaType, properties)
+	}
+	b.WriteString("  </manifest>\n")
+	return b.String()
+}
+
+// buildSpine renders the OPF <spine> element listing chapters in reading
+// order. toc="<ncxID>" is only emitted when an EPUB2 toc.ncx was generated.
+func buildSpine(chapters []epubItem, ncxID string) string {
+	var b strings.Builder
+
+	tocAttr := ""
+	if ncxID != "" {
+		tocAttr = fmt.Sprintf(` toc="%s"`, ncxID)
+	}
+	fmt.Fprintf(&b, "  <spine%s>\n", tocAttr)
+	for _, ch := range chapters {
+		fmt.Fprintf(&b, `    <itemref idref="%s"/>`+"\n", ch.ID)
+	}
+	b.WriteString("  </spine>\n")
+	return b.String()
+}
+
+// buildNav renders the EPUB3 nav.xhtml navigation document: a required
+// epub:type="toc" list of every chapter (nested under its Parts volume/arc
+// hierarchy, if any), plus an epub:type="landmarks" list of chapters with a
+// non-bodymatter ReferenceType (cover, toc, foreword, ...).
+func buildNav(book *Book, chapters []epubItem) []byte {
+	var toc strings.Builder
+	toc.WriteString(buildNavTree(chapters))
+
+	doc := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<head>
+<title>%s</title>
+<link rel="stylesheet" type="text/css" href="styles.css"/>
+</head>
+<body>
+<nav epub:type="toc" id="toc">
+<h1>Table of Contents</h1>
+%s</nav>
+%s</body>
+</html>
+`, html.EscapeString(book.Title), toc.String(), buildLandmarks(chapters))
+
+	return []byte(doc)
+}
+
+// buildNavTree renders chapters as a nested <ol>/<li> tree. Each "part" item
+// (see buildPartPage) sits at the same level as the chapters it groups, and
+// immediately opens a new nested <ol> - closed again once we reach a
+// sibling outside that group - so its own <li> ends up wrapping both its
+// heading link and the children's list, as EPUB nav trees expect. A plain
+// chapter's Parts is the group path it already sits inside, which by
+// insertPartPages's ordering guarantee is always the currently open path.
+func buildNavTree(chapters []epubItem) string {
+	var b strings.Builder
+	b.WriteString("<ol>\n")
+
+	var open []string
+	for _, ch := range chapters {
+		path := ch.Parts
+		if ch.RefType == "part" {
+			path = ch.Parts[:len(ch.Parts)-1]
+		}
+
+		common := 0
+		for common < len(open) && common < len(path) && open[common] == path[common] {
+			common++
+		}
+		for i := len(open); i > common; i-- {
+			b.WriteString("</ol></li>\n")
+		}
+		open = open[:common]
+
+		if ch.RefType == "part" {
+			fmt.Fprintf(&b, `  <li><a href="%s">%s</a>`+"\n<ol>\n", ch.Href, html.EscapeString(ch.Title))
+			open = ch.Parts
+		} else {
+			fmt.Fprintf(&b, `  <li><a href="%s">%s</a></li>`+"\n", ch.Href, html.EscapeString(ch.Title))
+		}
+	}
+	for i := len(open); i > 0; i-- {
+		b.WriteString("</ol></li>\n")
+	}
+
+	b.WriteString("</ol>\n")
+	return b.String()
+}
+
+func buildLandmarks(chapters []epubItem) string {
+	var entries []string
+	for _, ch := range chapters {
+		if ch.RefType == "" || ch.RefType == "bodymatter" || ch.RefType == "part" {
+			continue
+		}
+		entries = append(entries, fmt.Sprintf(`  <li><a epub:type="%s" href="%s">%s</a></li>`, ch.RefType, ch.Href, html.EscapeString(ch.Title)))
+	}
+	if len(entries) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(`<nav epub:type="landmarks" id="landmarks" hidden="">
+<h1>Landmarks</h1>
+<ol>
+%s
+</ol>
+</nav>
+`, strings.Join(entries, "\n"))
+}
+
+// buildNCX renders a toc.ncx document so EPUB2-only readers still get a
+// working table of contents, nesting navPoints under their volume/arc the
+// same way buildNavTree nests <li> elements.
+func buildNCX(book *Book, chapters []epubItem) []byte {
+	navPoints, depth := buildNCXTree(chapters)
+
+	doc := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+<head>
+<meta name="dtb:uid" content="%s"/>
+<meta name="dtb:depth" content="%d"/>
+<meta name="dtb:totalPageCount" content="0"/>
+<meta name="dtb:maxPageNumber" content="0"/>
+</head>
+<docTitle><text>%s</text></docTitle>
+<navMap>
+%s</navMap>
+</ncx>
+`, html.EscapeString(book.Identifier), depth, html.EscapeString(book.Title), navPoints)
+
+	return []byte(doc)
+}
+
+// buildNCXTree renders chapters as nested <navPoint> XML, sharing a single
+// incrementing playOrder across part-pages and chapters alike, and returns
+// the deepest nesting level reached (for the NCX's required dtb:depth meta).
+func buildNCXTree(chapters []epubItem) (string, int) {
+	var b strings.Builder
+
+	var open []string
+	playOrder := 0
+	maxDepth := 1
+	for _, ch := range chapters {
+		path := ch.Parts
+		if ch.RefType == "part" {
+			path = ch.Parts[:len(ch.Parts)-1]
+		}
+
+		common := 0
+		for common < len(open) && common < len(path) && open[common] == path[common] {
+			common++
+		}
+		for i := len(open); i > common; i-- {
+			b.WriteString("</navPoint>\n")
+		}
+		open = open[:common]
+
+		playOrder++
+		if d := len(open) + 1; d > maxDepth {
+			maxDepth = d
+		}
+		fmt.Fprintf(&b, `    <navPoint id="navpoint-%d" playOrder="%d">
+      <navLabel><text>%s</text></navLabel>
+      <content src="%s"/>
+`, playOrder, playOrder, html.EscapeString(ch.Title), ch.Href)
+
+		if ch.RefType == "part" {
+			open = ch.Parts
+		} else {
+			b.WriteString("    </navPoint>\n")
+		}
+	}
+	for i := len(open); i > 0; i-- {
+		b.WriteString("</navPoint>\n")
+	}
+
+	return b.String(), maxDepth
+}
+
+// buildOPF renders the content.opf package document: metadata (dc:* fields
+// plus calibre-style series meta), the manifest, and the spine.
+func buildOPF(book *Book, manifestXML, spineXML, coverID string) string {
+	lang := book.Lang
+	if lang == "" {
+		lang = "en"
+	}
+
+	var meta strings.Builder
+	fmt.Fprintf(&meta, `    <dc:identifier id="pub-id">%s</dc:identifier>`+"\n", html.EscapeString(book.Identifier))
+	fmt.Fprintf(&meta, `    <dc:title>%s</dc:title>`+"\n", html.EscapeString(book.Title))
+	fmt.Fprintf(&meta, `    <dc:creator>%s</dc:creator>`+"\n", html.EscapeString(book.Author))
+	fmt.Fprintf(&meta, `    <dc:language>%s</dc:language>`+"\n", html.EscapeString(lang))
+	if book.Description != "" {
+		fmt.Fprintf(&meta, `    <dc:description>%s</dc:description>`+"\n", html.EscapeString(book.Description))
+	}
+	if book.Rights != "" {
+		fmt.Fprintf(&meta, `    <dc:rights>%s</dc:rights>`+"\n", html.EscapeString(book.Rights))
+	}
+	if book.Publisher != "" {
+		fmt.Fprintf(&meta, `    <dc:publisher>%s</dc:publisher>`+"\n", html.EscapeString(book.Publisher))
+	}
+	if book.PubDate != "" {
+		fmt.Fprintf(&meta, `    <dc:date>%s</dc:date>`+"\n", html.EscapeString(book.PubDate))
+	}
+	if book.Series != "" {
+		fmt.Fprintf(&meta, `    <meta name="calibre:series" content="%s"/>`+"\n", html.EscapeString(book.Series))
+		if book.SeriesIndex > 0 {
+			fmt.Fprintf(&meta, `    <meta name="calibre:series_index" content="%s"/>`+"\n", strconv.FormatFloat(book.SeriesIndex, 'g', -1, 64))
+		}
+	}
+	if coverID != "" {
+		fmt.Fprintf(&meta, `    <meta name="cover" content="%s"/>`+"\n", coverID)
+	}
+
+	modified := book.CreatedAt
+	if modified.IsZero() {
+		modified = time.Now()
+	}
+	fmt.Fprintf(&meta, `    <meta property="dcterms:modified">%s</meta>`+"\n", modified.UTC().Format("2006-01-02T15:04:05Z"))
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="pub-id" xml:lang="%s">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+%s  </metadata>
+%s%s</package>
+`, html.EscapeString(lang), meta.String(), manifestXML, spineXML)
+}
+
 func getDefaultCSS() string {
 	return `body {
     font-family: Georgia, "Times New Roman", serif;
@@ -185,14 +765,18 @@ img {
     text-align: center;
     margin: 2em 0;
 }
-`
-}
 
-func formatChapterHTML(title, content string) string {
-	escapedTitle := html.EscapeString(title)
+.cover {
+    text-align: center;
+    margin: 0;
+    padding: 0;
+}
 
-	return fmt.Sprintf(`<h1 class="chapter-title">%s</h1>
-%s`, escapedTitle, content)
+.part-title {
+    text-align: center;
+    margin-top: 40%;
+}
+`
 }
 
 func sanitizeHTML(htmlContent string) string {
@@ -234,17 +818,3 @@ func normalizeForEPUB(htmlContent string) string {
 
 	return htmlContent
 }
-
-// CreateTableOfContents generates a table of contents chapter
-func CreateTableOfContents(chapters []Chapter) string {
-	var toc strings.Builder
-	toc.WriteString("<h1>Table of Contents</h1>\n<ul>\n")
-
-	for _, ch := range chapters {
-		escapedTitle := html.EscapeString(ch.Title)
-		toc.WriteString(fmt.Sprintf("  <li>%s</li>\n", escapedTitle))
-	}
-
-	toc.WriteString("</ul>\n")
-	return toc.String()
-}
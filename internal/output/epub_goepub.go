@@ -0,0 +1,184 @@
+package output
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	goepub "github.com/bmaupin/go-epub"
+
+	"web-to-epub-go/internal/formatter"
+)
+
+// goEPUBBuilder is an EPUBBuilder backed by github.com/bmaupin/go-epub
+// instead of GenerateEPUBWithOptions's direct, dependency-free packaging.
+// Selected via --epub-backend go-epub. It downloads/embeds book.Images and
+// the cover through go-epub's own asset handling (fed data URLs, since the
+// assets are already in memory via AssetManager) and rewrites each chapter's
+// "images/<filename>" src to the path go-epub assigns.
+type goEPUBBuilder struct{}
+
+func (goEPUBBuilder) Build(book *Book, outputPath string, opts *EPUBOptions) error {
+	if len(book.Chapters) == 0 {
+		return fmt.Errorf("no chapters to include in EPUB")
+	}
+
+	e := goepub.NewEpub(book.Title)
+	e.SetAuthor(book.Author)
+	if book.Lang != "" {
+		e.SetLang(book.Lang)
+	}
+	if book.Description != "" {
+		e.SetDescription(book.Description)
+	}
+	if book.Identifier != "" {
+		e.SetIdentifier(book.Identifier)
+	}
+
+	css := getDefaultCSS()
+	if theme := themeCSS(opts.Theme); theme != "" {
+		css += "\n" + theme
+	}
+	if opts.CustomCSS != "" {
+		css += "\n" + opts.CustomCSS
+	}
+	cssPath, err := e.AddCSS(dataURL("text/css", []byte(css)), "styles.css")
+	if err != nil {
+		return fmt.Errorf("failed to add stylesheet: %w", err)
+	}
+
+	imagePaths := map[string]string{}
+	if opts.EmbedImages {
+		for _, img := range book.Images {
+			p, err := e.AddImage(dataURL(img.MimeType, img.Data), img.Filename)
+			if err != nil {
+				fmt.Printf("⚠ Warning: skipping image %s: %v\n", img.Filename, err)
+				continue
+			}
+			imagePaths[img.Filename] = p
+		}
+
+		if len(book.Cover) > 0 {
+			coverPath, err := e.AddImage(dataURL(book.CoverType, book.Cover), "cover"+coverExtension(book.CoverType))
+			if err != nil {
+				fmt.Printf("⚠ Warning: failed to add cover: %v\n", err)
+			} else {
+				e.SetCover(coverPath, "")
+			}
+		}
+	}
+
+	parts := &goEPUBPartTracker{}
+	for _, ch := range book.Chapters {
+		parent := parts.enter(e, cssPath, ch.Parts)
+
+		content := rewriteImageSrcs(ch.Content, imagePaths)
+		cleanContent := sanitizeHTML(content)
+		xhtmlContent, err := formatter.ToXHTML(cleanContent)
+		if err != nil {
+			xhtmlContent = normalizeForEPUB(cleanContent)
+		}
+		body := fmt.Sprintf("<h1>%s</h1>\n%s", html.EscapeString(ch.Title), xhtmlContent)
+
+		if parent == "" {
+			_, err = e.AddSection(body, ch.Title, "", cssPath)
+		} else {
+			_, err = e.AddSubSection(parent, body, ch.Title, "", cssPath)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to add chapter %q: %w", ch.Title, err)
+		}
+	}
+
+	if err := os.MkdirAll(outputPath, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	filename := SanitizeFilename(book.Title) + ".epub"
+	fullPath := filepath.Join(outputPath, filename)
+	if err := e.Write(fullPath); err != nil {
+		return fmt.Errorf("failed to write EPUB: %w", err)
+	}
+
+	info, err := os.Stat(fullPath)
+	if err == nil {
+		fmt.Printf("✓ EPUB generated: %s (%s)\n", fullPath, FormatFileSize(info.Size()))
+	} else {
+		fmt.Printf("✓ EPUB generated: %s\n", fullPath)
+	}
+
+	return nil
+}
+
+// goEPUBPartTracker adds one landing-page section per volume/arc level the
+// first time it's entered, mirroring insertPartPages/buildNavTree's nesting
+// so go-epub's own TOC groups chapters under Chapter.Parts the same way the
+// direct builder's nav.xhtml/toc.ncx do. enter returns the go-epub section
+// filename a chapter with the given Parts should be added under via
+// AddSubSection, or "" for AddSection at the top level.
+type goEPUBPartTracker struct {
+	open  []string
+	stack []string
+}
+
+func (t *goEPUBPartTracker) enter(e *goepub.Epub, cssPath string, path []string) string {
+	common := 0
+	for common < len(t.open) && common < len(path) && t.open[common] == path[common] {
+		common++
+	}
+	t.open = t.open[:common]
+	t.stack = t.stack[:common]
+
+	for d := common; d < len(path); d++ {
+		title := path[d]
+		body := fmt.Sprintf("<h1>%s</h1>", html.EscapeString(title))
+
+		var filename string
+		var err error
+		if len(t.stack) == 0 {
+			filename, err = e.AddSection(body, title, "", cssPath)
+		} else {
+			filename, err = e.AddSubSection(t.stack[len(t.stack)-1], body, title, "", cssPath)
+		}
+		if err != nil {
+			fmt.Printf("⚠ Warning: failed to add part page %q: %v\n", title, err)
+		}
+
+		t.open = append(t.open, title)
+		t.stack = append(t.stack, filename)
+	}
+
+	if len(t.stack) == 0 {
+		return ""
+	}
+	return t.stack[len(t.stack)-1]
+}
+
+// dataURL encodes data as an RFC 2397 data URL, the form go-epub's AddCSS/
+// AddImage accept for in-memory content (we already have book assets decoded
+// in memory via AssetManager, so there's no source file/URL to point at).
+func dataURL(mimeType string, data []byte) string {
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	return fmt.Sprintf("data:%s;base64,%s", mimeType, base64.StdEncoding.EncodeToString(data))
+}
+
+var goEPUBImgSrcRe = regexp.MustCompile(`(?i)(<img[^>]*\bsrc\s*=\s*["'])images/([^"']+)(["'][^>]*>)`)
+
+// rewriteImageSrcs rewrites the AssetManager-assigned "images/<filename>" src
+// left on chapter <img> tags to the internal path go-epub assigned each
+// image when it was added to e. A filename with no entry in imagePaths
+// (skipped because AddImage failed, or never downloaded) is left as-is.
+func rewriteImageSrcs(contentHTML string, imagePaths map[string]string) string {
+	return goEPUBImgSrcRe.ReplaceAllStringFunc(contentHTML, func(tag string) string {
+		m := goEPUBImgSrcRe.FindStringSubmatch(tag)
+		if p, ok := imagePaths[m[2]]; ok {
+			return m[1] + p + m[3]
+		}
+		return tag
+	})
+}
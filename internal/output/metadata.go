@@ -19,9 +19,13 @@ type Book struct {
 	Rights      string
 	Publisher   string
 	Identifier  string
+	PubDate     string
+	Series      string
+	SeriesIndex float64
 	Cover       []byte
 	CoverType   string
 	Chapters    []Chapter
+	Images      []*Asset
 	CreatedAt   time.Time
 }
 
@@ -29,7 +33,17 @@ type Book struct {
 type Chapter struct {
 	Title   string
 	Content string
+	URL     string
 	Index   int
+	// ReferenceType classifies the chapter's role in the book for EPUB3
+	// landmark navigation (epub:type) and the EPUB2 guide, e.g. "cover",
+	// "toc", "foreword", "bodymatter", "appendix". Empty is treated as
+	// "bodymatter", the common case for scraped chapters.
+	ReferenceType string
+	// Parts is the chapter's path in a volume/arc hierarchy, e.g.
+	// ["Volume 1", "Arc 2"]. Empty means the chapter sits directly under
+	// the book root with no grouping.
+	Parts []string
 }
 
 // Metadata represents EPUB metadata
@@ -68,6 +82,21 @@ func (b *Book) AddChapter(title, content string) {
 	})
 }
 
+// RenderTo generates the book as "epub" (the default) or "pdf" into
+// outputPath, using each format's default options. Callers that need
+// config-driven options (themes, PDF page size, ...) should call
+// GenerateEPUBWithOptions/GeneratePDFWithOptions directly instead.
+func (b *Book) RenderTo(format, outputPath string) error {
+	switch format {
+	case "", "epub":
+		return GenerateEPUBWithOptions(b, outputPath, DefaultEPUBOptions())
+	case "pdf":
+		return GeneratePDFWithOptions(b, outputPath, DefaultPDFOptions())
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+}
+
 // GetMetadata returns the book's metadata
 func (b *Book) GetMetadata() *Metadata {
 	return &Metadata{
@@ -0,0 +1,338 @@
+package output
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+
+	"web-to-epub-go/internal/config"
+)
+
+// PDFOptions contains options for PDF generation
+type PDFOptions struct {
+	PageSize   string
+	FontFamily string
+	FontSize   float64
+	TitleSize  float64
+	// MarginMM is the page margin, in millimeters, applied on all four sides.
+	MarginMM   float64
+	IncludeTOC bool
+}
+
+// DefaultPDFOptions returns default PDF generation options
+func DefaultPDFOptions() *PDFOptions {
+	return &PDFOptions{
+		PageSize:   "A4",
+		FontFamily: "Times",
+		FontSize:   11,
+		TitleSize:  16,
+		MarginMM:   20,
+		IncludeTOC: true,
+	}
+}
+
+// GeneratePDF generates a PDF file from a book
+func GeneratePDF(book *Book, outputPath string) error {
+	return GeneratePDFWithOptions(book, outputPath, DefaultPDFOptions())
+}
+
+// PDFOptionsFromConfig converts the output.pdf config block into PDFOptions.
+// pc is assumed to already carry config.DefaultConfig's PDF defaults merged
+// with anything the user set, so fields are copied across as-is.
+func PDFOptionsFromConfig(pc config.PDFConfig) *PDFOptions {
+	return &PDFOptions{
+		PageSize:   pc.PageSize,
+		FontFamily: pc.FontFamily,
+		FontSize:   pc.FontSize,
+		TitleSize:  pc.TitleSize,
+		MarginMM:   pc.MarginMM,
+		IncludeTOC: pc.IncludeTOC,
+	}
+}
+
+// GeneratePDFWithOptions generates a PDF file with custom options. It embeds
+// book.Cover as a cover page and book.Images referenced from chapter HTML,
+// and, when opts.IncludeTOC is set, a chapter table of contents with real
+// page numbers - computed by rendering the book once through a throwaway
+// document (dryRunChapterPages) before rendering it for real.
+func GeneratePDFWithOptions(book *Book, outputPath string, opts *PDFOptions) error {
+	if len(book.Chapters) == 0 {
+		return fmt.Errorf("no chapters to include in PDF")
+	}
+
+	images := indexAssetsByFilename(book.Images)
+
+	var chapterPages []int
+	if opts.IncludeTOC {
+		chapterPages = dryRunChapterPages(book, opts, images)
+	}
+
+	pdf := newPDFDocument(opts)
+	pdf.SetTitle(book.Title, true)
+	pdf.SetAuthor(book.Author, true)
+
+	if len(book.Cover) > 0 {
+		writePDFCoverPage(pdf, book, opts)
+	}
+	if opts.IncludeTOC {
+		writePDFTOCPage(pdf, book, opts, chapterPages)
+	}
+	for _, ch := range book.Chapters {
+		writePDFChapter(pdf, ch, opts, images)
+	}
+
+	if err := pdf.Error(); err != nil {
+		return fmt.Errorf("failed to render PDF: %w", err)
+	}
+
+	if err := os.MkdirAll(outputPath, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	filename := SanitizeFilename(book.Title) + ".pdf"
+	fullPath := filepath.Join(outputPath, filename)
+
+	if err := pdf.OutputFileAndClose(fullPath); err != nil {
+		return fmt.Errorf("failed to write PDF: %w", err)
+	}
+
+	info, err := os.Stat(fullPath)
+	if err == nil {
+		fmt.Printf("✓ PDF generated: %s (%s)\n", fullPath, FormatFileSize(info.Size()))
+	} else {
+		fmt.Printf("✓ PDF generated: %s\n", fullPath)
+	}
+
+	return nil
+}
+
+func newPDFDocument(opts *PDFOptions) *fpdf.Fpdf {
+	pdf := fpdf.New("P", "mm", opts.PageSize, "")
+	pdf.SetMargins(opts.MarginMM, opts.MarginMM, opts.MarginMM)
+	pdf.SetAutoPageBreak(true, opts.MarginMM)
+	return pdf
+}
+
+// dryRunChapterPages renders the book once into a throwaway document - with
+// the same cover/TOC page budget as the real render - purely to learn which
+// page each chapter lands on, so writePDFTOCPage can list real page numbers
+// instead of guessing at them up front.
+func dryRunChapterPages(book *Book, opts *PDFOptions, images map[string]*Asset) []int {
+	pdf := newPDFDocument(opts)
+
+	if len(book.Cover) > 0 {
+		writePDFCoverPage(pdf, book, opts)
+	}
+	placeholderPages := make([]int, len(book.Chapters))
+	for i := range placeholderPages {
+		placeholderPages[i] = i + 1
+	}
+	writePDFTOCPage(pdf, book, opts, placeholderPages)
+
+	pages := make([]int, len(book.Chapters))
+	for i, ch := range book.Chapters {
+		pages[i] = writePDFChapter(pdf, ch, opts, images)
+	}
+	return pages
+}
+
+func writePDFCoverPage(pdf *fpdf.Fpdf, book *Book, opts *PDFOptions) {
+	pdf.AddPage()
+
+	imageType := fpdfImageType(book.CoverType)
+	if imageType == "" {
+		writePDFTitlePage(pdf, book, opts)
+		return
+	}
+
+	info := pdf.RegisterImageOptionsReader("cover", fpdf.ImageOptions{ImageType: imageType}, strings.NewReader(string(book.Cover)))
+	if pdf.Error() != nil {
+		return
+	}
+
+	pageW, pageH := pdf.GetPageSize()
+	left, top, right, bottom := pdf.GetMargins()
+	maxW, maxH := pageW-left-right, pageH-top-bottom
+
+	w, h := info.Extent()
+	scale := maxW / w
+	if h*scale > maxH {
+		scale = maxH / h
+	}
+	w, h = w*scale, h*scale
+
+	pdf.ImageOptions("cover", (pageW-w)/2, (pageH-h)/2, w, h, false, fpdf.ImageOptions{ImageType: imageType}, 0, "")
+}
+
+// writePDFTitlePage is the cover-page fallback used when there is no cover
+// image, or its format (e.g. SVG) can't be rasterized by fpdf.
+func writePDFTitlePage(pdf *fpdf.Fpdf, book *Book, opts *PDFOptions) {
+	pdf.SetFont(opts.FontFamily, "B", opts.TitleSize*1.5)
+	pdf.Ln(40)
+	pdf.MultiCell(0, opts.TitleSize, book.Title, "", "C", false)
+
+	pdf.SetFont(opts.FontFamily, "I", opts.TitleSize*0.75)
+	pdf.Ln(10)
+	pdf.MultiCell(0, opts.TitleSize*0.6, book.Author, "", "C", false)
+
+	if book.Description != "" {
+		pdf.SetFont(opts.FontFamily, "", opts.FontSize)
+		pdf.Ln(20)
+		pdf.MultiCell(0, opts.FontSize*0.6, book.Description, "", "C", false)
+	}
+}
+
+// writePDFTOCPage lays out one "Title .... page" line per chapter. pages
+// must have one entry per book.Chapters, in order.
+func writePDFTOCPage(pdf *fpdf.Fpdf, book *Book, opts *PDFOptions, pages []int) {
+	pdf.AddPage()
+
+	pdf.SetFont(opts.FontFamily, "B", opts.TitleSize)
+	pdf.MultiCell(0, opts.TitleSize*0.6, "Contents", "", "L", false)
+	pdf.Ln(opts.FontSize * 0.5)
+
+	pageW, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	const pageNumColWidth = 15
+	titleColWidth := pageW - left - right - pageNumColWidth
+
+	pdf.SetFont(opts.FontFamily, "", opts.FontSize)
+	for i, ch := range book.Chapters {
+		page := 0
+		if i < len(pages) {
+			page = pages[i]
+		}
+		pdf.CellFormat(titleColWidth, opts.FontSize*0.6, ch.Title, "", 0, "L", false, 0, "")
+		pdf.CellFormat(pageNumColWidth, opts.FontSize*0.6, strconv.Itoa(page), "", 1, "R", false, 0, "")
+	}
+}
+
+// writePDFChapter renders ch as its own page(s) - heading, then body with
+// any embedded images interleaved - and returns the page number it starts on.
+func writePDFChapter(pdf *fpdf.Fpdf, ch Chapter, opts *PDFOptions, images map[string]*Asset) int {
+	pdf.AddPage()
+	startPage := pdf.PageNo()
+
+	pdf.SetFont(opts.FontFamily, "B", opts.TitleSize)
+	pdf.Bookmark(ch.Title, 0, -1)
+	pdf.MultiCell(0, opts.TitleSize*0.6, ch.Title, "", "L", false)
+	pdf.Ln(opts.FontSize * 0.5)
+
+	writePDFChapterBody(pdf, ch.Content, opts, images)
+
+	return startPage
+}
+
+var pdfImgTagRe = regexp.MustCompile(`(?i)<img[^>]*\bsrc\s*=\s*["']([^"']+)["'][^>]*>`)
+
+// writePDFChapterBody splits ch's HTML on <img> tags, rendering the text in
+// between as justified paragraphs and each recognized image inline (scaled
+// down to the text column width if wider), in source order.
+func writePDFChapterBody(pdf *fpdf.Fpdf, contentHTML string, opts *PDFOptions, images map[string]*Asset) {
+	lastEnd := 0
+	for _, m := range pdfImgTagRe.FindAllStringSubmatchIndex(contentHTML, -1) {
+		writePDFTextBlock(pdf, contentHTML[lastEnd:m[0]], opts)
+
+		src := contentHTML[m[2]:m[3]]
+		writePDFChapterImage(pdf, src, opts, images)
+
+		lastEnd = m[1]
+	}
+	writePDFTextBlock(pdf, contentHTML[lastEnd:], opts)
+}
+
+func writePDFTextBlock(pdf *fpdf.Fpdf, htmlFragment string, opts *PDFOptions) {
+	text := htmlToPlainText(htmlFragment)
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	pdf.SetFont(opts.FontFamily, "", opts.FontSize)
+	pdf.MultiCell(0, opts.FontSize*0.6, text, "", "J", false)
+}
+
+// writePDFChapterImage resolves src (an AssetManager-rewritten "images/<file>"
+// path) to its Asset and embeds it at its natural size, scaled down to the
+// text column width if it is wider. Formats
+// fpdf can't decode (SVG) or srcs that don't resolve to a known asset (an
+// image AssetManager.Process left pointing at its original remote URL after
+// a failed download) are skipped rather than failing the whole chapter.
+func writePDFChapterImage(pdf *fpdf.Fpdf, src string, opts *PDFOptions, images map[string]*Asset) {
+	name := strings.TrimPrefix(src, "images/")
+	asset, ok := images[name]
+	if !ok {
+		return
+	}
+	imageType := fpdfImageType(asset.MimeType)
+	if imageType == "" {
+		return
+	}
+
+	info := pdf.RegisterImageOptionsReader(name, fpdf.ImageOptions{ImageType: imageType}, strings.NewReader(string(asset.Data)))
+	if pdf.Error() != nil {
+		return
+	}
+
+	pageW, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	maxW := pageW - left - right
+
+	w, h := info.Extent()
+	if w > maxW {
+		h *= maxW / w
+		w = maxW
+	}
+
+	pdf.Ln(opts.FontSize * 0.5)
+	pdf.ImageOptions(name, -1, -1, w, h, true, fpdf.ImageOptions{ImageType: imageType}, 0, "")
+	pdf.Ln(opts.FontSize * 0.5)
+}
+
+// indexAssetsByFilename maps an Asset.Filename back to the Asset itself, so
+// writePDFChapterImage can resolve the "images/<filename>" src left behind
+// by AssetManager.Process.
+func indexAssetsByFilename(assets []*Asset) map[string]*Asset {
+	out := make(map[string]*Asset, len(assets))
+	for _, a := range assets {
+		out[a.Filename] = a
+	}
+	return out
+}
+
+// fpdfImageType maps a MIME type to the ImageType string fpdf expects, or ""
+// if fpdf can't decode it (notably SVG, a vector format image.Decode and
+// fpdf can't rasterize - see the same carve-out in assets.go's encodeAsset).
+func fpdfImageType(mimeType string) string {
+	switch mimeType {
+	case "image/jpeg":
+		return "JPG"
+	case "image/png":
+		return "PNG"
+	case "image/gif":
+		return "GIF"
+	default:
+		return ""
+	}
+}
+
+var htmlTagRe = regexp.MustCompile(`<[^>]*>`)
+
+// htmlToPlainText strips markup from chapter HTML, leaving paragraph breaks
+// intact so MultiCell can wrap and justify the text like a typeset page.
+func htmlToPlainText(htmlContent string) string {
+	text := sanitizeHTML(htmlContent)
+	text = regexp.MustCompile(`(?i)</p>|<br\s*/?>`).ReplaceAllString(text, "\n")
+	text = htmlTagRe.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,25 @@
+package output
+
+// themeCSS returns the override stylesheet for a named bundled theme,
+// appended after getDefaultCSS() so it only needs to set what differs from
+// the default serif layout. An unknown or empty name yields no override.
+func themeCSS(name string) string {
+	switch name {
+	case "sans":
+		return `body { font-family: "Helvetica Neue", Arial, sans-serif; }
+p { text-align: left; text-indent: 0; }
+p + p { margin-top: 1em; }`
+	case "dark":
+		return `body { background-color: #1b1b1b; color: #e0e0e0; }
+a { color: #6fb3ff; }
+blockquote { border-left-color: #555; }
+pre { background-color: #262626; color: #e0e0e0; }
+hr { border-top-color: #444; }`
+	case "dyslexic":
+		return `body { font-family: "OpenDyslexic", "Comic Sans MS", sans-serif; line-height: 1.8; letter-spacing: 0.05em; }
+p { text-align: left; text-indent: 0; }
+p + p { margin-top: 1.2em; }`
+	default:
+		return ""
+	}
+}
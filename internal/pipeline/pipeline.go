@@ -0,0 +1,228 @@
+// Package pipeline implements a small post-processing DSL for scraped
+// chapters: named rules, loaded from a directory of JSON/YAML files, each
+// matching some part of a chapter (by regex or CSS selector) and applying
+// an action (replace, strip, annotate) to it. Rules run in order after
+// content extraction, so a user can strip site-specific boilerplate,
+// promote author's notes into annotated asides, or pull a chapter number
+// out of its title without writing Go code.
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"gopkg.in/yaml.v3"
+)
+
+// Rule is one named step of the post-processing pipeline.
+type Rule struct {
+	Name string `yaml:"name"`
+	// Type selects how Expression is matched: "css" runs it as a CSS
+	// selector against the chapter's content HTML; "regex" and "xpath" both
+	// run it as a regular expression (the two are equivalent here, mirroring
+	// extractor.XPathRegexStrategy's use of "xpath" as a regex-on-raw-HTML
+	// label rather than a real XPath engine).
+	Type       string `yaml:"type"`
+	Expression string `yaml:"expression"`
+	// Action is "replace" (substitute the match with Replacement),
+	// "strip" (remove the match), or "annotate" (for "regex"/"xpath", wrap
+	// the first match in a <span epub:type="Annotation">; for "css", set
+	// epub:type="Annotation" on every matched element).
+	Action string `yaml:"action"`
+	// Target is "title", "content", or "metadata". For "metadata", the
+	// rule's match (its first capture group, or the whole match if it has
+	// none) is recorded under MetadataKey instead of modifying the chapter.
+	Target      string `yaml:"target"`
+	Replacement string `yaml:"replacement,omitempty"`
+	Annotation  string `yaml:"annotation,omitempty"`
+	MetadataKey string `yaml:"metadataKey,omitempty"`
+}
+
+// Step records one rule's effect on a chapter, for --test --verbose to show
+// a before/after trace of the pipeline.
+type Step struct {
+	Rule   string
+	Target string
+	Before string
+	After  string
+}
+
+// Result is a chapter's title and content after every rule has run, plus
+// anything rules recorded into Target: "metadata", and a Steps trace.
+type Result struct {
+	Title    string
+	Content  string
+	Metadata map[string]string
+	Steps    []Step
+}
+
+// LoadRulesDir loads every YAML/JSON rule file in dir, applied in filename
+// order (os.ReadDir sorts its entries). A missing directory is not an error: it
+// simply yields no rules.
+func LoadRulesDir(dir string) ([]Rule, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scrapers directory: %w", err)
+	}
+
+	var rules []Rule
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read scraper rule %s: %w", path, err)
+		}
+
+		var rule Rule
+		if ext == ".json" {
+			err = json.Unmarshal(data, &rule)
+		} else {
+			err = yaml.Unmarshal(data, &rule)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse scraper rule %s: %w", path, err)
+		}
+
+		if rule.Name == "" {
+			rule.Name = strings.TrimSuffix(entry.Name(), ext)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// Apply runs rules against title/content in order, returning the result and
+// a before/after trace of each rule that actually matched something.
+func Apply(rules []Rule, title, content string) Result {
+	res := Result{Title: title, Content: content, Metadata: make(map[string]string)}
+
+	for _, rule := range rules {
+		before := res.Title
+		if rule.Target == "content" || rule.Target == "metadata" {
+			before = res.Content
+		}
+
+		after, captured, matched := applyRule(rule, res.Title, res.Content)
+		if !matched {
+			continue
+		}
+
+		switch rule.Target {
+		case "metadata":
+			if rule.MetadataKey != "" {
+				res.Metadata[rule.MetadataKey] = captured
+			}
+		case "content":
+			res.Content = after
+		default:
+			res.Title = after
+		}
+
+		res.Steps = append(res.Steps, Step{
+			Rule:   rule.Name,
+			Target: rule.Target,
+			Before: before,
+			After:  after,
+		})
+	}
+
+	return res
+}
+
+// applyRule runs a single rule against title/content, returning the new
+// value of whichever it targets (content for "content"/"metadata" targets,
+// title otherwise), the captured text for a "metadata" target, and whether
+// the rule matched anything at all.
+func applyRule(rule Rule, title, content string) (result, captured string, matched bool) {
+	source := title
+	if rule.Target == "content" || rule.Target == "metadata" {
+		source = content
+	}
+
+	switch rule.Type {
+	case "css":
+		return applyCSSRule(rule, source)
+	default: // "regex" and "xpath" are equivalent
+		return applyRegexRule(rule, source)
+	}
+}
+
+func applyRegexRule(rule Rule, source string) (result, captured string, matched bool) {
+	re, err := regexp.Compile(rule.Expression)
+	if err != nil {
+		return source, "", false
+	}
+
+	loc := re.FindStringSubmatchIndex(source)
+	if loc == nil {
+		return source, "", false
+	}
+
+	match := source[loc[0]:loc[1]]
+	captured = match
+	if len(loc) >= 4 && loc[2] >= 0 {
+		captured = source[loc[2]:loc[3]]
+	}
+
+	if rule.Target == "metadata" {
+		return source, captured, true
+	}
+
+	switch rule.Action {
+	case "strip":
+		return re.ReplaceAllString(source, ""), captured, true
+	case "annotate":
+		annotated := fmt.Sprintf(`<span epub:type="%s">%s</span>`, rule.Annotation, match)
+		return source[:loc[0]] + annotated + source[loc[1]:], captured, true
+	default: // "replace"
+		return re.ReplaceAllString(source, rule.Replacement), captured, true
+	}
+}
+
+func applyCSSRule(rule Rule, source string) (result, captured string, matched bool) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(source))
+	if err != nil {
+		return source, "", false
+	}
+
+	sel := doc.Find(rule.Expression)
+	if sel.Length() == 0 {
+		return source, "", false
+	}
+	captured = sel.First().Text()
+
+	switch rule.Action {
+	case "strip":
+		sel.Remove()
+	case "annotate":
+		sel.Each(func(_ int, s *goquery.Selection) {
+			s.SetAttr("epub:type", rule.Annotation)
+		})
+	default: // "replace"
+		sel.SetHtml(rule.Replacement)
+	}
+
+	html, err := doc.Find("body").Html()
+	if err != nil {
+		return source, captured, true
+	}
+	return html, captured, true
+}
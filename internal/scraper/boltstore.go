@@ -0,0 +1,93 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltStateStore is a StateStore backed by a single bbolt file. Chapters are
+// grouped into one bucket per book identifier, keyed by chapter URL.
+type BoltStateStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStateStore opens (creating if necessary) a bbolt-backed state store
+// at path.
+func NewBoltStateStore(path string) (*BoltStateStore, error) {
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state store: %w", err)
+	}
+	return &BoltStateStore{db: db}, nil
+}
+
+func (s *BoltStateStore) Get(bookID, url string) (*ChapterState, bool, error) {
+	var state ChapterState
+	found := false
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(bookID))
+		if bucket == nil {
+			return nil
+		}
+		data := bucket.Get([]byte(url))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, &state)
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read state: %w", err)
+	}
+	if !found {
+		return nil, false, nil
+	}
+	return &state, true, nil
+}
+
+func (s *BoltStateStore) Put(bookID, url string, state ChapterState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(bookID))
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(url), data)
+	})
+}
+
+func (s *BoltStateStore) All(bookID string) ([]ChapterState, error) {
+	var states []ChapterState
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(bookID))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			var state ChapterState
+			if err := json.Unmarshal(v, &state); err != nil {
+				return err
+			}
+			states = append(states, state)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+
+	return states, nil
+}
+
+func (s *BoltStateStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,93 @@
+package scraper
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// cacheEntry holds one cached response body plus the validators needed to
+// conditionally revalidate it (If-None-Match / If-Modified-Since) instead of
+// re-downloading unchanged pages on a later fetch within the same process.
+// Entries that have no
+// validators at all (e.g. a cached robots.txt body) instead rely on
+// fetchedAt for a simple client-side TTL.
+type cacheEntry struct {
+	url          string
+	body         []byte
+	etag         string
+	lastModified string
+	fetchedAt    time.Time
+}
+
+// htmlCache is an in-memory LRU cache of fetched page bodies, bounded by
+// total body bytes rather than entry count since chapter pages vary wildly
+// in size. The least-recently-used entry is evicted first when a new or
+// updated entry would push the cache over maxBytes.
+type htmlCache struct {
+	mutex    sync.Mutex
+	maxBytes int64
+	curBytes int64
+	order    *list.List // front = most recently used
+	entries  map[string]*list.Element
+}
+
+// newHTMLCache creates a cache bounded to maxBytes of cached bodies. A
+// non-positive maxBytes disables caching entirely (Get always misses, Put is
+// a no-op) so callers don't need a separate on/off switch.
+func newHTMLCache(maxBytes int64) *htmlCache {
+	return &htmlCache{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get returns the cached entry for url, if any, marking it most-recently-used.
+func (c *htmlCache) Get(url string) (cacheEntry, bool) {
+	if c.maxBytes <= 0 {
+		return cacheEntry{}, false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	elem, ok := c.entries[url]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(cacheEntry), true
+}
+
+// Put inserts or replaces the cached entry for entry.url, evicting
+// least-recently-used entries until the cache fits within maxBytes.
+func (c *htmlCache) Put(entry cacheEntry) {
+	if c.maxBytes <= 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if elem, ok := c.entries[entry.url]; ok {
+		c.curBytes -= int64(len(elem.Value.(cacheEntry).body))
+		c.order.Remove(elem)
+		delete(c.entries, entry.url)
+	}
+
+	entry.fetchedAt = time.Now()
+	c.entries[entry.url] = c.order.PushFront(entry)
+	c.curBytes += int64(len(entry.body))
+
+	for c.curBytes > c.maxBytes && c.order.Len() > 0 {
+		oldest := c.order.Back()
+		if oldest == nil {
+			break
+		}
+		c.order.Remove(oldest)
+		old := oldest.Value.(cacheEntry)
+		delete(c.entries, old.url)
+		c.curBytes -= int64(len(old.body))
+	}
+}
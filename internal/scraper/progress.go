@@ -0,0 +1,251 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/vbauerster/mpb/v8"
+	"github.com/vbauerster/mpb/v8/decor"
+)
+
+// ScrapeStats summarizes the outcome of a completed scrape, passed to
+// ProgressReporter.OnFinished.
+type ScrapeStats struct {
+	TotalChapters int
+	Scraped       int
+	Errors        int
+	TotalBytes    int
+	Duration      time.Duration
+}
+
+// ProgressReporter receives structured scraping lifecycle events. Implementations
+// must be safe to use from the goroutine(s) driving the scrape.
+type ProgressReporter interface {
+	// OnDiscovered is called once chapter discovery has produced a total count.
+	OnDiscovered(total int)
+	// OnChapterStart is called right before a chapter fetch begins.
+	OnChapterStart(idx int, url string)
+	// OnChapterDone is called after a chapter fetch finishes, successfully or not.
+	OnChapterDone(idx int, bytes int, err error)
+	// OnFinished is called once after the whole scrape completes.
+	OnFinished(stats ScrapeStats)
+	// OnPackagingStart is called once output generation begins, with the
+	// number of discrete steps (preparing chapters, embedding assets, writing
+	// the file).
+	OnPackagingStart(total int)
+	// OnPackagingStep is called as each packaging step begins, in order.
+	OnPackagingStep(name string)
+	// OnPackagingDone is called once the output file has been written.
+	OnPackagingDone()
+}
+
+// NoOpReporter discards all progress events. It is the default for library use,
+// where printing to stdout would be unwelcome.
+type NoOpReporter struct{}
+
+func (NoOpReporter) OnDiscovered(total int)                      {}
+func (NoOpReporter) OnChapterStart(idx int, url string)          {}
+func (NoOpReporter) OnChapterDone(idx int, bytes int, err error) {}
+func (NoOpReporter) OnFinished(stats ScrapeStats)                {}
+func (NoOpReporter) OnPackagingStart(total int)                  {}
+func (NoOpReporter) OnPackagingStep(name string)                 {}
+func (NoOpReporter) OnPackagingDone()                            {}
+
+// jsonEvent is the wire format emitted by JSONReporter, one object per line.
+type jsonEvent struct {
+	Event string       `json:"event"`
+	Index int          `json:"index,omitempty"`
+	Total int          `json:"total,omitempty"`
+	URL   string       `json:"url,omitempty"`
+	Bytes int          `json:"bytes,omitempty"`
+	Error string       `json:"error,omitempty"`
+	Step  string       `json:"step,omitempty"`
+	Stats *ScrapeStats `json:"stats,omitempty"`
+}
+
+// JSONReporter writes one JSON object per line, suitable for piping scrape
+// progress into other tools.
+type JSONReporter struct {
+	w io.Writer
+}
+
+// NewJSONReporter creates a JSONReporter that writes events to w.
+func NewJSONReporter(w io.Writer) *JSONReporter {
+	return &JSONReporter{w: w}
+}
+
+func (r *JSONReporter) emit(e jsonEvent) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(r.w, string(data))
+}
+
+func (r *JSONReporter) OnDiscovered(total int) {
+	r.emit(jsonEvent{Event: "discovered", Total: total})
+}
+
+func (r *JSONReporter) OnChapterStart(idx int, url string) {
+	r.emit(jsonEvent{Event: "chapter_start", Index: idx, URL: url})
+}
+
+func (r *JSONReporter) OnChapterDone(idx int, bytes int, err error) {
+	e := jsonEvent{Event: "chapter_done", Index: idx, Bytes: bytes}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	r.emit(e)
+}
+
+func (r *JSONReporter) OnFinished(stats ScrapeStats) {
+	r.emit(jsonEvent{Event: "finished", Stats: &stats})
+}
+
+func (r *JSONReporter) OnPackagingStart(total int) {
+	r.emit(jsonEvent{Event: "packaging_start", Total: total})
+}
+
+func (r *JSONReporter) OnPackagingStep(name string) {
+	r.emit(jsonEvent{Event: "packaging_step", Step: name})
+}
+
+func (r *JSONReporter) OnPackagingDone() {
+	r.emit(jsonEvent{Event: "packaging_done"})
+}
+
+// PlainReporter writes one plain log line per event, the non-TUI fallback
+// used under --no-tui or --verbose so scrape progress stays legible when
+// stdout isn't an interactive terminal (e.g. CI logs).
+type PlainReporter struct {
+	w io.Writer
+}
+
+// NewPlainReporter creates a PlainReporter writing lines to w.
+func NewPlainReporter(w io.Writer) *PlainReporter {
+	return &PlainReporter{w: w}
+}
+
+func (r *PlainReporter) OnDiscovered(total int) {
+	fmt.Fprintf(r.w, "discovered %d chapters\n", total)
+}
+
+func (r *PlainReporter) OnChapterStart(idx int, url string) {
+	fmt.Fprintf(r.w, "fetching chapter %d: %s\n", idx, url)
+}
+
+func (r *PlainReporter) OnChapterDone(idx int, bytes int, err error) {
+	if err != nil {
+		fmt.Fprintf(r.w, "chapter %d failed: %v\n", idx, err)
+		return
+	}
+	fmt.Fprintf(r.w, "chapter %d done (%d bytes)\n", idx, bytes)
+}
+
+func (r *PlainReporter) OnFinished(stats ScrapeStats) {
+	fmt.Fprintf(r.w, "finished: %d/%d chapters, %d errors, %d bytes, %s\n",
+		stats.Scraped, stats.TotalChapters, stats.Errors, stats.TotalBytes, stats.Duration.Round(time.Millisecond))
+}
+
+func (r *PlainReporter) OnPackagingStart(total int) {
+	fmt.Fprintf(r.w, "packaging output (%d steps)\n", total)
+}
+
+func (r *PlainReporter) OnPackagingStep(name string) {
+	fmt.Fprintf(r.w, "packaging: %s\n", name)
+}
+
+func (r *PlainReporter) OnPackagingDone() {
+	fmt.Fprintln(r.w, "packaging done")
+}
+
+// TerminalReporter renders live progress with mpb bars: one tracking overall
+// chapter completion, one per in-flight chapter fetch labelled with its index
+// and URL, and a final bar for output packaging. A chapter bar's byte count
+// is set only when its fetch completes; bytes are not counted during the
+// download.
+type TerminalReporter struct {
+	progress *mpb.Progress
+	overall  *mpb.Bar
+	pkgBar   *mpb.Bar
+
+	mu          sync.Mutex
+	chapterBars map[int]*mpb.Bar
+}
+
+// NewTerminalReporter creates a TerminalReporter writing to the default
+// mpb output (stdout).
+func NewTerminalReporter() *TerminalReporter {
+	return &TerminalReporter{
+		progress:    mpb.New(mpb.WithWidth(40)),
+		chapterBars: make(map[int]*mpb.Bar),
+	}
+}
+
+func (r *TerminalReporter) OnDiscovered(total int) {
+	r.overall = r.progress.AddBar(int64(total),
+		mpb.PrependDecorators(
+			decor.Name("chapters", decor.WC{W: 10}),
+			decor.CountersNoUnit("%d / %d", decor.WCSyncWidth),
+		),
+		mpb.AppendDecorators(decor.Percentage()),
+	)
+}
+
+func (r *TerminalReporter) OnChapterStart(idx int, url string) {
+	if r.overall == nil {
+		r.OnDiscovered(0)
+	}
+
+	bar := r.progress.AddBar(0,
+		mpb.BarRemoveOnComplete(),
+		mpb.PrependDecorators(decor.Name(fmt.Sprintf("ch %d %s", idx, url), decor.WC{W: 10})),
+		mpb.AppendDecorators(decor.CountersKiloByte("% .1f / % .1f")),
+	)
+
+	r.mu.Lock()
+	r.chapterBars[idx] = bar
+	r.mu.Unlock()
+}
+
+func (r *TerminalReporter) OnChapterDone(idx int, bytes int, err error) {
+	r.mu.Lock()
+	bar := r.chapterBars[idx]
+	delete(r.chapterBars, idx)
+	r.mu.Unlock()
+
+	if bar != nil {
+		bar.SetTotal(int64(bytes), true)
+		bar.SetCurrent(int64(bytes))
+	}
+	if r.overall != nil {
+		r.overall.Increment()
+	}
+}
+
+func (r *TerminalReporter) OnFinished(stats ScrapeStats) {
+	r.progress.Wait()
+}
+
+func (r *TerminalReporter) OnPackagingStart(total int) {
+	r.pkgBar = r.progress.AddBar(int64(total),
+		mpb.PrependDecorators(decor.Name("packaging", decor.WC{W: 10})),
+		mpb.AppendDecorators(decor.CountersNoUnit("%d / %d")),
+	)
+}
+
+func (r *TerminalReporter) OnPackagingStep(name string) {
+	if r.pkgBar == nil {
+		r.OnPackagingStart(1)
+	}
+	r.pkgBar.Increment()
+}
+
+func (r *TerminalReporter) OnPackagingDone() {
+	if r.pkgBar != nil {
+		r.pkgBar.SetCurrent(r.pkgBar.Current())
+	}
+}
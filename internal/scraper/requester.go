@@ -15,24 +15,110 @@ import (
 
 // Requester handles HTTP requests with rate limiting and politeness
 type Requester struct {
-	client      *http.Client
-	config      config.PoliteConfig
-	userAgent   string
-	lastRequest time.Time
-	mutex       sync.Mutex
-	robotsCache map[string]*RobotsRules
-	robotsMutex sync.RWMutex
+	client    *http.Client
+	config    config.PoliteConfig
+	userAgent string
+	limiter   *hostLimiter
+	// cache stores both fetched page bodies and fetched robots.txt bodies,
+	// keyed by URL - getRobotsRules re-parses its cached body on every call
+	// rather than caching *RobotsRules directly, so there's one shared
+	// bytes-bounded LRU instead of a second, unbounded cache just for robots.
+	cache *htmlCache
+}
+
+// hostLimiter enforces a minimum delay between requests to the same host, so
+// a caller fetching many URLs concurrently (e.g. the scraper's worker pool)
+// can hit different hosts in parallel while still being polite to any one
+// of them. The delay for a given host is max(delay, hostDelay[host]) - the
+// configured default, or a per-host override learned from that host's own
+// robots.txt Crawl-delay directive, whichever asks for more patience.
+type hostLimiter struct {
+	mu        sync.Mutex
+	delay     time.Duration
+	hostDelay map[string]time.Duration
+	next      map[string]time.Time
+}
+
+func newHostLimiter(delay time.Duration) *hostLimiter {
+	return &hostLimiter{
+		delay:     delay,
+		hostDelay: make(map[string]time.Duration),
+		next:      make(map[string]time.Time),
+	}
+}
+
+// setHostDelay records host's own Crawl-delay, so subsequent wait calls for
+// that host use max(l.delay, delay) instead of just l.delay. A zero delay
+// (no Crawl-delay directive) clears any previous override.
+func (l *hostLimiter) setHostDelay(host string, delay time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if delay <= 0 {
+		delete(l.hostDelay, host)
+		return
+	}
+	l.hostDelay[host] = delay
+}
+
+func (l *hostLimiter) effectiveDelay(host string) time.Duration {
+	delay := l.delay
+	if hostDelay, ok := l.hostDelay[host]; ok && hostDelay > delay {
+		delay = hostDelay
+	}
+	return delay
+}
+
+func (l *hostLimiter) wait(ctx context.Context, rawURL string) error {
+	host := hostname(rawURL)
+
+	l.mu.Lock()
+	now := time.Now()
+	start := now
+	if next, ok := l.next[host]; ok && next.After(start) {
+		start = next
+	}
+	l.next[host] = start.Add(l.effectiveDelay(host))
+	l.mu.Unlock()
+
+	wait := time.Until(start)
+	if wait <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func hostname(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Hostname()
 }
 
 // RobotsRules represents parsed robots.txt rules
 type RobotsRules struct {
 	Disallowed []string
 	CrawlDelay time.Duration
-	Fetched    time.Time
+	// Sitemaps lists every "Sitemap:" directive found in the file. Unlike
+	// Disallow/Crawl-delay these apply regardless of which User-agent block
+	// (if any) they appear in, per the sitemaps.org convention.
+	Sitemaps []string
+	Fetched  time.Time
 }
 
-// NewRequester creates a new HTTP requester with rate limiting
-func NewRequester(politeConfig config.PoliteConfig, userAgent string, timeout int) *Requester {
+// NewRequester creates a new HTTP requester with rate limiting. cacheSizeBytes
+// bounds the in-memory LRU cache of fetched bodies used for conditional
+// revalidation; 0 disables caching.
+func NewRequester(politeConfig config.PoliteConfig, userAgent string, timeout int, cacheSizeBytes int64) *Requester {
 	if userAgent == "" {
 		userAgent = "Mozilla/5.0 (compatible; WebToEPUB/1.0)"
 	}
@@ -50,15 +136,23 @@ func NewRequester(politeConfig config.PoliteConfig, userAgent string, timeout in
 				return nil
 			},
 		},
-		config:      politeConfig,
-		userAgent:   userAgent,
-		robotsCache: make(map[string]*RobotsRules),
+		config:    politeConfig,
+		userAgent: userAgent,
+		limiter:   newHostLimiter(time.Duration(politeConfig.DelayMS) * time.Millisecond),
+		cache:     newHTMLCache(cacheSizeBytes),
 	}
 }
 
-// Fetch fetches a URL with rate limiting and politeness
+// Fetch fetches a URL with per-host rate limiting and politeness. Requests
+// to different hosts can proceed concurrently; requests to the same host
+// are spaced at least DelayMS apart (longer if robots.txt sets a larger
+// Crawl-delay) regardless of how many callers are fetching at
+// once, so a caller running several fetches in parallel doesn't need its
+// own rate limiting on top of this.
 func (r *Requester) Fetch(ctx context.Context, targetURL string) ([]byte, error) {
-	r.waitForRateLimit()
+	if err := r.limiter.wait(ctx, targetURL); err != nil {
+		return nil, err
+	}
 
 	if r.config.RespectRobotsTxt {
 		allowed, err := r.isAllowedByRobots(targetURL)
@@ -79,12 +173,26 @@ func (r *Requester) Fetch(ctx context.Context, targetURL string) ([]byte, error)
 	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
 	req.Header.Set("Connection", "keep-alive")
 
+	cached, hasCached := r.cache.Get(targetURL)
+	if hasCached {
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+
 	resp, err := r.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && hasCached {
+		return cached.body, nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
@@ -94,21 +202,139 @@ func (r *Requester) Fetch(ctx context.Context, targetURL string) ([]byte, error)
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	if etag := resp.Header.Get("ETag"); etag != "" || resp.Header.Get("Last-Modified") != "" {
+		r.cache.Put(cacheEntry{
+			url:          targetURL,
+			body:         body,
+			etag:         etag,
+			lastModified: resp.Header.Get("Last-Modified"),
+		})
+	}
+
 	return body, nil
 }
 
-func (r *Requester) waitForRateLimit() {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
+// Result is one URL's outcome from FetchMany.
+type Result struct {
+	URL  string
+	Body []byte
+	Err  error
+}
+
+// FetchMany fetches every url in urls, using a worker pool bounded by
+// r.config.MaxConcurrent - the same concurrency knob WebScraper.runPool
+// applies to chapter fetches - and streams each Result back as it completes.
+// Fetch's per-host pacing still applies, so workers only gain real
+// concurrency across URLs on different hosts. The returned channel is
+// closed once every URL has been fetched or ctx is done.
+func (r *Requester) FetchMany(ctx context.Context, urls []string) <-chan Result {
+	out := make(chan Result)
+
+	concurrency := r.config.MaxConcurrent
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(urls) {
+		concurrency = len(urls)
+	}
+
+	go func() {
+		defer close(out)
+		if concurrency == 0 {
+			return
+		}
+
+		jobs := make(chan string)
+		go func() {
+			defer close(jobs)
+			for _, u := range urls {
+				select {
+				case jobs <- u:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		var workers sync.WaitGroup
+		for i := 0; i < concurrency; i++ {
+			workers.Add(1)
+			go func() {
+				defer workers.Done()
+				for u := range jobs {
+					body, err := r.Fetch(ctx, u)
+					select {
+					case out <- Result{URL: u, Body: body, Err: err}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+		}
+		workers.Wait()
+	}()
+
+	return out
+}
+
+// Unchanged performs a conditional GET against targetURL using etag (e.g.
+// one persisted in a StateStore from a previous run) and reports whether
+// the server confirms the content hasn't changed (304), without reading a
+// full body either way. Used to decide whether a "resume" skip is still
+// safe or the chapter needs re-fetching.
+func (r *Requester) Unchanged(ctx context.Context, targetURL, etag string) (bool, error) {
+	if etag == "" {
+		return false, nil
+	}
 
-	delay := time.Duration(r.config.DelayMS) * time.Millisecond
-	elapsed := time.Since(r.lastRequest)
+	if err := r.limiter.wait(ctx, targetURL); err != nil {
+		return false, err
+	}
 
-	if elapsed < delay {
-		time.Sleep(delay - elapsed)
+	req, err := http.NewRequestWithContext(ctx, "GET", targetURL, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
 	}
+	req.Header.Set("User-Agent", r.userAgent)
+	req.Header.Set("If-None-Match", etag)
 
-	r.lastRequest = time.Now()
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode == http.StatusNotModified, nil
+}
+
+// LastETag returns the ETag captured by the most recent Fetch of targetURL
+// within this process, if the server sent one. Used to persist a
+// per-chapter ETag to an external StateStore across runs.
+func (r *Requester) LastETag(targetURL string) (string, bool) {
+	entry, ok := r.cache.Get(targetURL)
+	if !ok || entry.etag == "" {
+		return "", false
+	}
+	return entry.etag, true
+}
+
+// DiscoverSitemaps returns the "Sitemap:" directives listed in siteURL's
+// host's robots.txt, for callers that don't have an explicit sitemap URL
+// configured.
+func (r *Requester) DiscoverSitemaps(siteURL string) ([]string, error) {
+	parsedURL, err := url.Parse(siteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	domain := parsedURL.Scheme + "://" + parsedURL.Host
+	rules, err := r.getRobotsRules(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	return rules.Sitemaps, nil
 }
 
 func (r *Requester) isAllowedByRobots(targetURL string) (bool, error) {
@@ -137,16 +363,20 @@ func (r *Requester) isAllowedByRobots(targetURL string) (bool, error) {
 	return true, nil
 }
 
+// getRobotsRules fetches and parses domain's robots.txt, reusing r.cache -
+// the same bytes-bounded LRU Fetch uses for page bodies - instead of a
+// separate, unbounded robots.txt cache. The raw body is cached rather than
+// the parsed *RobotsRules so there's one eviction policy for both; it's
+// re-parsed (cheap) on every call that hits the cache.
 func (r *Requester) getRobotsRules(domain string) (*RobotsRules, error) {
-	r.robotsMutex.RLock()
-	rules, exists := r.robotsCache[domain]
-	r.robotsMutex.RUnlock()
+	robotsURL := domain + "/robots.txt"
 
-	if exists && time.Since(rules.Fetched) < time.Hour {
+	if cached, ok := r.cache.Get(robotsURL); ok && time.Since(cached.fetchedAt) < time.Hour {
+		rules := parseRobotsTxt(string(cached.body))
+		rules.Fetched = cached.fetchedAt
 		return rules, nil
 	}
 
-	robotsURL := domain + "/robots.txt"
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -171,12 +401,12 @@ func (r *Requester) getRobotsRules(domain string) (*RobotsRules, error) {
 		return &RobotsRules{Fetched: time.Now()}, nil
 	}
 
-	rules = parseRobotsTxt(string(body))
+	r.cache.Put(cacheEntry{url: robotsURL, body: body})
+
+	rules := parseRobotsTxt(string(body))
 	rules.Fetched = time.Now()
 
-	r.robotsMutex.Lock()
-	r.robotsCache[domain] = rules
-	r.robotsMutex.Unlock()
+	r.limiter.setHostDelay(hostname(domain), rules.CrawlDelay)
 
 	return rules, nil
 }
@@ -219,6 +449,10 @@ func parseRobotsTxt(content string) *RobotsRules {
 				fmt.Sscanf(value, "%f", &delay)
 				rules.CrawlDelay = time.Duration(delay * float64(time.Second))
 			}
+		case "sitemap":
+			if value != "" {
+				rules.Sitemaps = append(rules.Sitemaps, value)
+			}
 		}
 	}
 
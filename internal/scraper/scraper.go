@@ -3,15 +3,21 @@ package scraper
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"web-to-epub-go/internal/config"
 	"web-to-epub-go/internal/extractor"
+	"web-to-epub-go/internal/formatter"
 	"web-to-epub-go/internal/navigator"
 	"web-to-epub-go/internal/output"
+	"web-to-epub-go/internal/pipeline"
 )
 
 // Chapter represents a scraped chapter
@@ -20,16 +26,56 @@ type Chapter struct {
 	Content string
 	URL     string
 	Index   int
+	// Metadata holds values captured by "metadata"-targeted pipeline rules
+	// (e.g. a chapter number pulled out of the title), keyed by the rule's
+	// MetadataKey.
+	Metadata map[string]string
 }
 
 // WebScraper orchestrates the scraping process
 type WebScraper struct {
-	config    *config.Config
-	requester *Requester
-	navigator *navigator.ChapterNavigator
-	strategy  extractor.DetectionStrategy
-	chapters  []Chapter
-	options   *extractor.ExtractionOptions
+	config        *config.Config
+	requester     *Requester
+	navigator     *navigator.ChapterNavigator
+	strategy      extractor.DetectionStrategy
+	chapters      []Chapter
+	options       *extractor.ExtractionOptions
+	reporter      ProgressReporter
+	store         StateStore
+	bookID        string
+	force         bool
+	pipelineRules []pipeline.Rule
+	epubBackend   string
+	theme         string
+}
+
+// SetPipelineRules sets the post-processing rules run against every
+// chapter's title/content after extraction (see package pipeline).
+func (ws *WebScraper) SetPipelineRules(rules []pipeline.Rule) {
+	ws.pipelineRules = rules
+}
+
+// SetProgressReporter sets the reporter used to emit scraping lifecycle
+// events. If unset, scraping is silent (NoOpReporter).
+func (ws *WebScraper) SetProgressReporter(r ProgressReporter) {
+	if r == nil {
+		r = NoOpReporter{}
+	}
+	ws.reporter = r
+}
+
+// SetForce controls whether fetchAndExtract re-fetches URLs that already
+// have a "scraped" entry in the state store.
+func (ws *WebScraper) SetForce(force bool) {
+	ws.force = force
+}
+
+// SetEPUBOptions selects the EPUB backend (see output.NewEPUBBuilder) and
+// bundled theme (see output.EPUBOptions.Theme) used by Generate. An empty
+// backend/theme keeps the defaults ("legacy" backend, "serif" theme).
+func (ws *WebScraper) SetEPUBOptions(backend, theme string) {
+	ws.epubBackend = backend
+	ws.theme = theme
 }
 
 // NewWebScraper creates a new web scraper instance
@@ -38,6 +84,7 @@ func NewWebScraper(cfg *config.Config) (*WebScraper, error) {
 		cfg.Scraping.Polite,
 		cfg.Scraping.UserAgent,
 		cfg.Scraping.Timeout,
+		cfg.Scraping.CacheSizeBytes,
 	)
 
 	nav := navigator.NewChapterNavigator(cfg.Navigation)
@@ -52,30 +99,105 @@ func NewWebScraper(cfg *config.Config) (*WebScraper, error) {
 		CleanScripts:     true,
 	}
 
-	return &WebScraper{
+	ws := &WebScraper{
 		config:    cfg,
 		requester: requester,
 		navigator: nav,
 		strategy:  strategy,
 		chapters:  make([]Chapter, 0),
 		options:   options,
-	}, nil
+		reporter:  NoOpReporter{},
+		bookID:    output.SanitizeFilename(cfg.Book.Title),
+	}
+
+	if cfg.Scraping.StatePath != "" {
+		store, err := NewBoltStateStore(cfg.Scraping.StatePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open state store: %w", err)
+		}
+		ws.store = store
+	}
+
+	return ws, nil
+}
+
+// Resume rebuilds the scraper's chapter list from a state store at path
+// without re-fetching anything from the network, so a saved crawl can be
+// turned straight into an EPUB.
+func (ws *WebScraper) Resume(path string) error {
+	store, err := NewBoltStateStore(path)
+	if err != nil {
+		return fmt.Errorf("failed to open state store: %w", err)
+	}
+	ws.store = store
+
+	states, err := store.All(ws.bookID)
+	if err != nil {
+		return fmt.Errorf("failed to load saved state: %w", err)
+	}
+
+	sort.Slice(states, func(i, j int) bool { return states[i].Index < states[j].Index })
+
+	ws.chapters = ws.chapters[:0]
+	for _, state := range states {
+		if state.Status != "scraped" {
+			continue
+		}
+		ws.chapters = append(ws.chapters, Chapter{
+			Title:   state.Title,
+			Content: state.Content,
+			URL:     state.URL,
+			Index:   state.Index,
+		})
+	}
+
+	if len(ws.chapters) == 0 {
+		return fmt.Errorf("no scraped chapters found in state store")
+	}
+
+	return nil
 }
 
 // ScrapeAll scrapes all chapters based on configuration
 func (ws *WebScraper) ScrapeAll() error {
-	ctx := context.Background()
+	return ws.ScrapeAllCtx(context.Background())
+}
+
+// ScrapeAllCtx scrapes all chapters based on configuration, aborting cleanly
+// if ctx is cancelled so a long crawl can be interrupted mid-run.
+func (ws *WebScraper) ScrapeAllCtx(ctx context.Context) error {
+	start := time.Now()
 
+	var err error
 	switch ws.config.Navigation.Method {
 	case "url_pattern":
-		return ws.scrapeByPattern(ctx)
+		err = ws.scrapeByPattern(ctx)
 	case "next_link":
-		return ws.scrapeByNextLink(ctx)
+		err = ws.scrapeByNextLink(ctx)
 	case "toc":
-		return ws.scrapeByTOC(ctx)
+		err = ws.scrapeByTOC(ctx)
+	case "feed":
+		err = ws.scrapeByFeed(ctx)
+	case "sitemap":
+		err = ws.scrapeBySitemap(ctx)
 	default:
-		return fmt.Errorf("unknown navigation method: %s", ws.config.Navigation.Method)
+		err = fmt.Errorf("unknown navigation method: %s", ws.config.Navigation.Method)
+	}
+
+	_, _, errors := ws.navigator.GetProgress()
+	totalBytes := 0
+	for _, ch := range ws.chapters {
+		totalBytes += len(ch.Content)
 	}
+	ws.reporter.OnFinished(ScrapeStats{
+		TotalChapters: len(ws.chapters),
+		Scraped:       len(ws.chapters),
+		Errors:        errors,
+		TotalBytes:    totalBytes,
+		Duration:      time.Since(start),
+	})
+
+	return err
 }
 
 func (ws *WebScraper) scrapeByPattern(ctx context.Context) error {
@@ -84,55 +206,68 @@ func (ws *WebScraper) scrapeByPattern(ctx context.Context) error {
 		return fmt.Errorf("failed to discover chapters: %w", err)
 	}
 
-	fmt.Printf("📖 Found %d chapters to scrape\n\n", len(chapters))
-
-	for i, chapterInfo := range chapters {
-		fmt.Printf("⏳ [%d/%d] Scraping: %s\n", i+1, len(chapters), chapterInfo.URL)
-
-		if err := ws.scrapeChapter(ctx, chapterInfo.URL, chapterInfo.Index); err != nil {
-			fmt.Printf("  ✗ Error: %v\n", err)
-			ws.navigator.UpdateChapterStatus(chapterInfo.Index, "error")
-		} else {
-			ws.navigator.UpdateChapterStatus(chapterInfo.Index, "scraped")
-		}
-
-		total, scraped, errors := ws.navigator.GetProgress()
-		fmt.Printf("  📊 Progress: %d/%d scraped, %d errors\n\n", scraped, total, errors)
-	}
+	ws.reporter.OnDiscovered(len(chapters))
 
-	return nil
+	scraped, err := ws.runPool(ctx, chapters)
+	ws.chapters = append(ws.chapters, scraped...)
+	return err
 }
 
 func (ws *WebScraper) scrapeByNextLink(ctx context.Context) error {
-	currentURL := ws.config.Scraping.StartURL
-	index := 1
 	maxChapters := ws.config.Navigation.MaxChapters
 	if maxChapters <= 0 {
 		maxChapters = 10000
 	}
 
-	fmt.Printf("📖 Starting next-link navigation from: %s\n\n", currentURL)
+	// prefetch holds at most one in-flight background fetch. next_link
+	// chains are inherently sequential -- the URL for chapter i+1 is only
+	// known once chapter i has been parsed -- so true N-chapter lookahead
+	// isn't possible here. What we can still do is overlap the network
+	// fetch of i+1 with the extraction/bookkeeping of i, which is what
+	// startFetch below buys us. Per-host pacing happens inside
+	// Requester.Fetch itself, so this needs no limiter of its own.
+	type prefetchResult struct {
+		body []byte
+		err  error
+	}
+	startFetch := func(targetURL string) chan prefetchResult {
+		ch := make(chan prefetchResult, 1)
+		go func() {
+			body, err := ws.requester.Fetch(ctx, targetURL)
+			ch <- prefetchResult{body: body, err: err}
+		}()
+		return ch
+	}
+
+	currentURL := ws.config.Scraping.StartURL
+	index := 1
+	prefetch := startFetch(currentURL)
 
 	for index <= maxChapters {
-		fmt.Printf("⏳ [%d] Scraping: %s\n", index, currentURL)
-
+		ws.reporter.OnChapterStart(index, currentURL)
 		ws.navigator.MarkVisited(currentURL)
 
-		body, err := ws.requester.Fetch(ctx, currentURL)
-		if err != nil {
-			fmt.Printf("  ✗ Error fetching: %v\n", err)
+		res := <-prefetch
+		if res.err != nil {
+			ws.reporter.OnChapterDone(index, 0, fmt.Errorf("failed to fetch: %w", res.err))
 			break
 		}
+		body := res.body
 
 		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
 		if err != nil {
-			fmt.Printf("  ✗ Error parsing: %v\n", err)
+			ws.reporter.OnChapterDone(index, len(body), fmt.Errorf("failed to parse: %w", err))
 			break
 		}
 
+		nextURL, found := ws.navigator.FindNextChapterLink(doc, currentURL)
+		if found && index < maxChapters {
+			prefetch = startFetch(nextURL)
+		}
+
 		content, err := ws.strategy.Extract(doc, ws.options)
 		if err != nil {
-			fmt.Printf("  ✗ Error extracting content: %v\n", err)
+			ws.reporter.OnChapterDone(index, len(body), fmt.Errorf("failed to extract content: %w", err))
 		} else {
 			title := extractor.ExtractTitle(doc, ws.options.TitleSelector, ws.options.TitleRegex)
 			if title == "" {
@@ -148,12 +283,10 @@ func (ws *WebScraper) scrapeByNextLink(ctx context.Context) error {
 				Index:   index,
 			})
 
-			fmt.Printf("  ✓ Extracted: %s (%d chars)\n", title, len(content))
+			ws.reporter.OnChapterDone(index, len(body), nil)
 		}
 
-		nextURL, found := ws.navigator.FindNextChapterLink(doc, currentURL)
 		if !found {
-			fmt.Println("\n📍 No more chapters found (next link not found)")
 			break
 		}
 
@@ -161,7 +294,6 @@ func (ws *WebScraper) scrapeByNextLink(ctx context.Context) error {
 		index++
 	}
 
-	fmt.Printf("\n📊 Total chapters scraped: %d\n", len(ws.chapters))
 	return nil
 }
 
@@ -171,8 +303,6 @@ func (ws *WebScraper) scrapeByTOC(ctx context.Context) error {
 		return fmt.Errorf("TOC URL not configured")
 	}
 
-	fmt.Printf("📖 Fetching table of contents: %s\n\n", tocURL)
-
 	body, err := ws.requester.Fetch(ctx, tocURL)
 	if err != nil {
 		return fmt.Errorf("failed to fetch TOC: %w", err)
@@ -188,45 +318,247 @@ func (ws *WebScraper) scrapeByTOC(ctx context.Context) error {
 		return fmt.Errorf("failed to parse TOC links: %w", err)
 	}
 
-	fmt.Printf("📖 Found %d chapters in TOC\n\n", len(chapters))
+	ws.reporter.OnDiscovered(len(chapters))
 
-	for i, chapterInfo := range chapters {
-		fmt.Printf("⏳ [%d/%d] Scraping: %s\n", i+1, len(chapters), chapterInfo.Title)
+	// Chapters are fetched concurrently here, so the heuristic the old
+	// sequential loop used (borrow the previous chapter's title when the
+	// TOC label looked wrong) no longer has a well-defined "previous"
+	// chapter and is dropped.
+	scraped, err := ws.runPool(ctx, chapters)
+	ws.chapters = append(ws.chapters, scraped...)
+	return err
+}
 
-		if err := ws.scrapeChapter(ctx, chapterInfo.URL, chapterInfo.Index); err != nil {
-			fmt.Printf("  ✗ Error: %v\n", err)
-			ws.navigator.UpdateChapterStatus(chapterInfo.Index, "error")
-		} else {
-			if len(ws.chapters) > 0 {
-				lastChapter := ws.chapters[len(ws.chapters)-1]
-				if lastChapter.Title != "" && lastChapter.Title != chapterInfo.Title {
-					ws.navigator.UpdateChapterTitle(chapterInfo.Index, lastChapter.Title)
-				}
+func (ws *WebScraper) scrapeByFeed(ctx context.Context) error {
+	feedURL := ws.config.Navigation.FeedURL
+	if feedURL == "" {
+		return fmt.Errorf("feed URL not configured")
+	}
+
+	body, err := ws.requester.Fetch(ctx, feedURL)
+	if err != nil {
+		return fmt.Errorf("failed to fetch feed: %w", err)
+	}
+
+	chapters, err := ws.navigator.ParseFeed(body)
+	if err != nil {
+		return fmt.Errorf("failed to parse feed: %w", err)
+	}
+
+	ws.reporter.OnDiscovered(len(chapters))
+
+	scraped, err := ws.runPool(ctx, chapters)
+	ws.chapters = append(ws.chapters, scraped...)
+	return err
+}
+
+func (ws *WebScraper) scrapeBySitemap(ctx context.Context) error {
+	var sitemapURLs []string
+	if ws.config.Navigation.SitemapURL != "" {
+		sitemapURLs = []string{ws.config.Navigation.SitemapURL}
+	} else {
+		sites, err := ws.requester.DiscoverSitemaps(ws.config.Scraping.StartURL)
+		if err != nil || len(sites) == 0 {
+			return fmt.Errorf("sitemap URL not configured and none found in robots.txt")
+		}
+		sitemapURLs = sites
+	}
+
+	entries, err := ws.fetchSitemapEntries(ctx, sitemapURLs, 0)
+	if err != nil {
+		return err
+	}
+
+	chapters, err := ws.navigator.ParseSitemap(entries)
+	if err != nil {
+		return fmt.Errorf("failed to parse sitemap: %w", err)
+	}
+
+	ws.reporter.OnDiscovered(len(chapters))
+
+	scraped, err := ws.runPool(ctx, chapters)
+	ws.chapters = append(ws.chapters, scraped...)
+	return err
+}
+
+// maxSitemapDepth bounds <sitemapindex> recursion in fetchSitemapEntries,
+// guarding against a cyclic or pathologically deep sitemap chain.
+const maxSitemapDepth = 5
+
+// fetchSitemapEntries fetches each sitemap URL, recursing into any
+// <sitemapindex> children - the common case on the large archive/catalog
+// sites this tool targets, which split their sitemap across multiple files
+// - and returns the combined <url> entries from every <urlset> reached.
+func (ws *WebScraper) fetchSitemapEntries(ctx context.Context, sitemapURLs []string, depth int) ([]navigator.SitemapURLEntry, error) {
+	if depth > maxSitemapDepth {
+		return nil, fmt.Errorf("sitemap index nesting exceeds max depth (%d)", maxSitemapDepth)
+	}
+
+	var entries []navigator.SitemapURLEntry
+	for _, sitemapURL := range sitemapURLs {
+		body, err := ws.requester.Fetch(ctx, sitemapURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch sitemap %s: %w", sitemapURL, err)
+		}
+
+		urlEntries, childSitemaps, err := navigator.ParseSitemapDocument(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse sitemap %s: %w", sitemapURL, err)
+		}
+
+		if len(childSitemaps) > 0 {
+			childEntries, err := ws.fetchSitemapEntries(ctx, childSitemaps, depth+1)
+			if err != nil {
+				return nil, err
 			}
-			ws.navigator.UpdateChapterStatus(chapterInfo.Index, "scraped")
+			entries = append(entries, childEntries...)
+			continue
 		}
 
-		total, scraped, errors := ws.navigator.GetProgress()
-		fmt.Printf("  📊 Progress: %d/%d scraped, %d errors\n\n", scraped, total, errors)
+		entries = append(entries, urlEntries...)
+	}
+	return entries, nil
+}
+
+// runPool dispatches fetch+extract jobs for infos to a worker pool bounded
+// by cfg.Scraping.Polite.MaxConcurrent. Requester.Fetch paces requests per
+// host internally, so workers can pull jobs for different hosts without
+// waiting on each other while still being polite to any single host.
+// Results are collected into a slice ordered by Chapter.Index, so the
+// resulting chapter order is deterministic regardless of completion order.
+func (ws *WebScraper) runPool(ctx context.Context, infos []navigator.ChapterInfo) ([]Chapter, error) {
+	if len(infos) == 0 {
+		return nil, nil
 	}
 
-	return nil
+	concurrency := ws.config.Scraping.Polite.MaxConcurrent
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(infos) {
+		concurrency = len(infos)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan navigator.ChapterInfo)
+
+	type outcome struct {
+		info    navigator.ChapterInfo
+		chapter Chapter
+		bytes   int
+		err     error
+	}
+	results := make(chan outcome)
+
+	var workers sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for info := range jobs {
+				ws.reporter.OnChapterStart(info.Index, info.URL)
+
+				chapter, n, err := ws.fetchAndExtract(ctx, info.URL, info.Index)
+				results <- outcome{info: info, chapter: chapter, bytes: n, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, info := range infos {
+			select {
+			case jobs <- info:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	position := make(map[int]int, len(infos))
+	for i, info := range infos {
+		position[info.Index] = i
+	}
+
+	ordered := make([]Chapter, len(infos))
+	have := make([]bool, len(infos))
+
+	var firstErr error
+	for res := range results {
+		if res.err != nil {
+			ws.navigator.UpdateChapterStatus(res.info.Index, "error")
+			if firstErr == nil && ctx.Err() != nil {
+				firstErr = ctx.Err()
+			}
+		} else {
+			ws.navigator.UpdateChapterStatus(res.info.Index, "scraped")
+			pos := position[res.info.Index]
+			ordered[pos] = res.chapter
+			have[pos] = true
+		}
+		ws.reporter.OnChapterDone(res.info.Index, res.bytes, res.err)
+	}
+
+	chapters := make([]Chapter, 0, len(infos))
+	for i, ok := range have {
+		if ok {
+			chapters = append(chapters, ordered[i])
+		}
+	}
+
+	return chapters, firstErr
 }
 
-func (ws *WebScraper) scrapeChapter(ctx context.Context, url string, index int) error {
-	body, err := ws.requester.Fetch(ctx, url)
+// fetchAndExtract fetches and extracts a single chapter, returning the
+// number of raw bytes fetched for progress reporting. It touches no shared
+// scraper state besides the state store (safe for concurrent use), so it
+// can be called from multiple runPool workers at once. If a state store is
+// attached and already holds a "scraped" entry for url, the cached result
+// is reused instead of re-fetching — unless force is set, or the entry has
+// a stored ETag and a conditional request reports the page has changed
+// since, in which case it's re-fetched like any other chapter.
+func (ws *WebScraper) fetchAndExtract(ctx context.Context, chapterURL string, index int) (Chapter, int, error) {
+	if ws.store != nil && !ws.force {
+		if cached, found, err := ws.store.Get(ws.bookID, chapterURL); err == nil && found && cached.Status == "scraped" {
+			cachedChapter := Chapter{
+				Title:   cached.Title,
+				Content: cached.Content,
+				URL:     chapterURL,
+				Index:   index,
+			}
+			if cached.ETag == "" {
+				return cachedChapter, len(cached.Content), nil
+			}
+			if unchanged, err := ws.requester.Unchanged(ctx, chapterURL, cached.ETag); err == nil && unchanged {
+				return cachedChapter, len(cached.Content), nil
+			}
+		}
+	}
+
+	body, err := ws.requester.Fetch(ctx, chapterURL)
 	if err != nil {
-		return fmt.Errorf("failed to fetch: %w", err)
+		ws.saveState(chapterURL, index, "", "", "", "error")
+		return Chapter{}, 0, fmt.Errorf("failed to fetch: %w", err)
 	}
+	etag, _ := ws.requester.LastETag(chapterURL)
 
 	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("failed to parse HTML: %w", err)
+		ws.saveState(chapterURL, index, "", "", etag, "error")
+		return Chapter{}, len(body), fmt.Errorf("failed to parse HTML: %w", err)
 	}
 
 	content, err := ws.strategy.Extract(doc, ws.options)
 	if err != nil {
-		return fmt.Errorf("failed to extract content: %w", err)
+		ws.saveState(chapterURL, index, "", "", etag, "error")
+		return Chapter{}, len(body), fmt.Errorf("failed to extract content: %w", err)
 	}
 
 	title := extractor.ExtractTitle(doc, ws.options.TitleSelector, ws.options.TitleRegex)
@@ -236,43 +568,132 @@ func (ws *WebScraper) scrapeChapter(ctx context.Context, url string, index int)
 
 	content = extractor.CleanContent(content)
 
-	ws.chapters = append(ws.chapters, Chapter{
-		Title:   title,
-		Content: content,
+	var metadata map[string]string
+	if len(ws.pipelineRules) > 0 {
+		result := pipeline.Apply(ws.pipelineRules, title, content)
+		title, content, metadata = result.Title, result.Content, result.Metadata
+	}
+
+	chapter := Chapter{
+		Title:    title,
+		Content:  content,
+		URL:      chapterURL,
+		Index:    index,
+		Metadata: metadata,
+	}
+	ws.saveState(chapterURL, index, title, content, etag, "scraped")
+
+	return chapter, len(body), nil
+}
+
+// saveState persists a chapter's outcome to the state store, if one is
+// attached. Failures are ignored: the store is a resume optimization, not a
+// source of truth for the current run.
+func (ws *WebScraper) saveState(url string, index int, title, content, etag, status string) {
+	if ws.store == nil {
+		return
+	}
+
+	state := ChapterState{
 		URL:     url,
 		Index:   index,
-	})
-
-	fmt.Printf("  ✓ Extracted: %s (%d chars)\n", title, len(content))
-	return nil
+		Title:   title,
+		Content: content,
+		ETag:    etag,
+		Hash:    fmt.Sprintf("%x", sha256.Sum256([]byte(content))),
+		Status:  status,
+	}
+	_ = ws.store.Put(ws.bookID, url, state)
 }
 
-// GenerateEPUB generates an EPUB from scraped chapters
-func (ws *WebScraper) GenerateEPUB(outputPath string) error {
+// Generate renders the scraped chapters to outputPath in the format
+// selected by the config's output.format field ("epub" or "pdf").
+func (ws *WebScraper) Generate(outputPath string) error {
 	if len(ws.chapters) == 0 {
-		return fmt.Errorf("no chapters to generate EPUB from")
+		return fmt.Errorf("no chapters to generate output from")
 	}
 
+	ws.reporter.OnPackagingStart(3)
+	defer ws.reporter.OnPackagingDone()
+
+	ws.reporter.OnPackagingStep("preparing chapters")
 	outputChapters := make([]output.Chapter, len(ws.chapters))
 	for i, ch := range ws.chapters {
 		outputChapters[i] = output.Chapter{
 			Title:   ch.Title,
 			Content: ch.Content,
+			URL:     ch.URL,
 			Index:   ch.Index,
+			Parts:   deriveParts(ch.Content, ch.Title, ws.config.Navigation),
 		}
 	}
 
+	ws.reporter.OnPackagingStep("embedding assets")
+	assets := output.NewAssetManager(ws.requester, ws.config.Output.Assets)
+	embedImages := ws.config.Output.Assets.Enabled
+	if embedImages {
+		outputChapters = assets.Process(context.Background(), outputChapters)
+	}
+
+	rights := ws.config.Book.Rights
+	if rights == "" {
+		rights = ws.config.Output.EPUBMetadata.Rights
+	}
+	publisher := ws.config.Book.Publisher
+	if publisher == "" {
+		publisher = ws.config.Output.EPUBMetadata.Publisher
+	}
+
+	lang := ws.config.Output.EPUBMetadata.Lang
+	if lang == "auto" {
+		lang = detectChaptersLanguage(outputChapters)
+	}
+
 	book := &output.Book{
 		Title:       ws.config.Book.Title,
 		Author:      ws.config.Book.Author,
 		Description: ws.config.Book.Description,
 		Chapters:    outputChapters,
-		Lang:        ws.config.Output.EPUBMetadata.Lang,
-		Rights:      ws.config.Output.EPUBMetadata.Rights,
-		Publisher:   ws.config.Output.EPUBMetadata.Publisher,
+		Images:      assets.Assets(),
+		Lang:        lang,
+		Rights:      rights,
+		Publisher:   publisher,
+		Identifier:  ws.config.Output.EPUBMetadata.Identifier,
+		PubDate:     ws.config.Book.PubDate,
+		Series:      ws.config.Book.Series,
+		SeriesIndex: ws.config.Book.SeriesIndex,
+	}
+
+	if ws.config.Book.Cover != "" {
+		cover, err := assets.FetchCover(context.Background(), ws.config.Book.Cover, ws.config.Book.CoverFit)
+		if err != nil {
+			fmt.Printf("⚠ Warning: failed to load cover %s: %v\n", ws.config.Book.Cover, err)
+		} else {
+			book.Cover = cover.Data
+			book.CoverType = cover.MimeType
+		}
+	}
+	if book.Cover == nil {
+		if cover := assets.Cover(); cover != nil {
+			book.Cover = cover.Data
+			book.CoverType = cover.MimeType
+		}
 	}
 
-	return output.GenerateEPUB(book, outputPath)
+	ws.reporter.OnPackagingStep("writing output file")
+	switch ws.config.Output.Format {
+	case "pdf":
+		return output.GeneratePDFWithOptions(book, outputPath, output.PDFOptionsFromConfig(ws.config.Output.PDF))
+	default:
+		opts := output.DefaultEPUBOptions()
+		opts.EmbedImages = embedImages
+		opts.Theme = ws.theme
+		builder, err := output.NewEPUBBuilder(ws.epubBackend)
+		if err != nil {
+			return err
+		}
+		return builder.Build(book, outputPath, opts)
+	}
 }
 
 // GetChapters returns the scraped chapters
@@ -306,6 +727,8 @@ func buildStrategy(cfg config.ContentDetectionConfig) extractor.DetectionStrateg
 			MaxDepth: cfg.DOMPosition.MaxDepth,
 			MinWidth: cfg.DOMPosition.MinWidth,
 		}
+	case "readability":
+		return &extractor.ReadabilityStrategy{}
 	case "hybrid":
 		strategies := []extractor.DetectionStrategy{}
 
@@ -321,6 +744,8 @@ func buildStrategy(cfg config.ContentDetectionConfig) extractor.DetectionStrateg
 			MinBlockSize:    cfg.TextDensity.MinBlockSize,
 		})
 
+		strategies = append(strategies, &extractor.ReadabilityStrategy{})
+
 		strategies = append(strategies, &extractor.DOMPositionStrategy{
 			MaxDepth: cfg.DOMPosition.MaxDepth,
 			MinWidth: cfg.DOMPosition.MinWidth,
@@ -332,24 +757,98 @@ func buildStrategy(cfg config.ContentDetectionConfig) extractor.DetectionStrateg
 	}
 }
 
+// languageSampleBytes caps how much chapter content detectChaptersLanguage
+// feeds into formatter.DetectLanguage, so "auto" language detection stays
+// cheap even on a book with hundreds of chapters.
+const languageSampleBytes = 20000
+
+// detectChaptersLanguage concatenates content from the start of the book,
+// up to languageSampleBytes, and runs it through formatter.DetectLanguage.
+// Early chapters are assumed representative of the whole book's language.
+func detectChaptersLanguage(chapters []output.Chapter) string {
+	var sample strings.Builder
+	for _, ch := range chapters {
+		sample.WriteString(ch.Content)
+		sample.WriteString(" ")
+		if sample.Len() >= languageSampleBytes {
+			break
+		}
+	}
+	text := sample.String()
+	if len(text) > languageSampleBytes {
+		text = text[:languageSampleBytes]
+	}
+	return formatter.DetectLanguage(text)
+}
+
+// deriveParts computes a chapter's volume/arc path for a nested table of
+// contents. It prefers cfg.PartSelector matched against the chapter's own
+// content (e.g. an in-page "Volume 1 > Arc 2" breadcrumb), falling back to
+// cfg.PartRegex applied to the chapter title when the selector is unset or
+// finds nothing.
+func deriveParts(content, title string, cfg config.NavigationConfig) []string {
+	if cfg.PartSelector != "" {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+		if err == nil {
+			if text := strings.TrimSpace(doc.Find(cfg.PartSelector).First().Text()); text != "" {
+				return splitParts(text, cfg.PartSeparator)
+			}
+		}
+	}
+
+	if cfg.PartRegex != "" {
+		re, err := regexp.Compile(cfg.PartRegex)
+		if err == nil {
+			if matches := re.FindStringSubmatch(title); len(matches) > 1 {
+				var parts []string
+				for _, m := range matches[1:] {
+					if m = strings.TrimSpace(m); m != "" {
+						parts = append(parts, m)
+					}
+				}
+				return parts
+			}
+		}
+	}
+
+	return nil
+}
+
+func splitParts(text, sep string) []string {
+	if sep == "" {
+		sep = ">"
+	}
+	var parts []string
+	for _, p := range strings.Split(text, sep) {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
 // ScrapeTest scrapes a single URL for testing configuration
-func (ws *WebScraper) ScrapeTest(url string) (*Chapter, error) {
+// ScrapeTest fetches and extracts a single chapter without persisting any
+// state, and also returns a before/after trace of every pipeline rule that
+// matched (see package pipeline), so --test --verbose can show how a rules
+// file will affect a real chapter before running a full scrape with it.
+func (ws *WebScraper) ScrapeTest(url string) (*Chapter, []pipeline.Step, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	body, err := ws.requester.Fetch(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch: %w", err)
+		return nil, nil, fmt.Errorf("failed to fetch: %w", err)
 	}
 
 	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse HTML: %w", err)
 	}
 
 	content, err := ws.strategy.Extract(doc, ws.options)
 	if err != nil {
-		return nil, fmt.Errorf("failed to extract content: %w", err)
+		return nil, nil, fmt.Errorf("failed to extract content: %w", err)
 	}
 
 	title := extractor.ExtractTitle(doc, ws.options.TitleSelector, ws.options.TitleRegex)
@@ -359,12 +858,20 @@ func (ws *WebScraper) ScrapeTest(url string) (*Chapter, error) {
 
 	content = extractor.CleanContent(content)
 
+	var metadata map[string]string
+	var steps []pipeline.Step
+	if len(ws.pipelineRules) > 0 {
+		result := pipeline.Apply(ws.pipelineRules, title, content)
+		title, content, metadata, steps = result.Title, result.Content, result.Metadata, result.Steps
+	}
+
 	return &Chapter{
-		Title:   title,
-		Content: content,
-		URL:     url,
-		Index:   1,
-	}, nil
+		Title:    title,
+		Content:  content,
+		URL:      url,
+		Index:    1,
+		Metadata: metadata,
+	}, steps, nil
 }
 
 // PrintSummary prints a summary of the scraping results
@@ -0,0 +1,26 @@
+package scraper
+
+// ChapterState is the persisted record for a single scraped chapter, keyed
+// by book identifier and source URL.
+type ChapterState struct {
+	URL     string
+	Index   int
+	Title   string
+	Content string
+	ETag    string
+	Hash    string
+	Status  string
+}
+
+// StateStore persists per-URL scrape results so long crawls can resume
+// after a crash without re-fetching already-scraped chapters.
+type StateStore interface {
+	// Get returns the stored state for url within bookID, if any.
+	Get(bookID, url string) (*ChapterState, bool, error)
+	// Put saves or overwrites the state for url within bookID.
+	Put(bookID, url string, state ChapterState) error
+	// All returns every stored chapter state for bookID, in no particular order.
+	All(bookID string) ([]ChapterState, error)
+	// Close releases any resources held by the store.
+	Close() error
+}
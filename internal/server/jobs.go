@@ -0,0 +1,249 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"web-to-epub-go/internal/config"
+	"web-to-epub-go/internal/pipeline"
+	"web-to-epub-go/internal/scraper"
+)
+
+// Job tracks one in-flight or completed --serve scrape, started by POST
+// /api/jobs and followed by a client over GET /api/jobs/events. Status and
+// Error are mutated by finish() under jobManager.mu, so a *Job must never be
+// JSON-encoded directly - use jobManager.status to take a race-free
+// snapshot instead.
+type Job struct {
+	ID     string
+	Title  string
+	Status string // "running", "done", "failed"
+	Error  string
+
+	events chan interface{}
+}
+
+// JobStatus is a point-in-time, JSON-encodable snapshot of a Job's exported
+// fields, taken while holding jobManager.mu.
+type JobStatus struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// jobManager runs scrape jobs in the background and keeps their terminal
+// state around so GET /api/jobs can list history after a job finishes.
+type jobManager struct {
+	mu   sync.Mutex
+	jobs map[string]*Job
+	next int
+}
+
+func newJobManager() *jobManager {
+	return &jobManager{jobs: make(map[string]*Job)}
+}
+
+// Start builds a scraper for cfg and runs it in a new goroutine, returning
+// immediately with a snapshot of the freshly-created Job; progress streams
+// over Job.events, fetched separately via Get.
+func (m *jobManager) Start(cfg *config.Config, rulesDir, scrapersDir string) JobStatus {
+	m.mu.Lock()
+	m.next++
+	id := strconv.Itoa(m.next)
+	m.mu.Unlock()
+
+	job := &Job{
+		ID:     id,
+		Title:  cfg.Book.Title,
+		Status: "running",
+		events: make(chan interface{}, 64),
+	}
+
+	m.mu.Lock()
+	m.jobs[id] = job
+	status := m.status(job)
+	m.mu.Unlock()
+
+	go m.run(job, cfg, rulesDir, scrapersDir)
+
+	return status
+}
+
+func (m *jobManager) run(job *Job, cfg *config.Config, rulesDir, scrapersDir string) {
+	defer close(job.events)
+
+	ws, err := newScraperFor(cfg, rulesDir, scrapersDir)
+	if err != nil {
+		m.finish(job, err)
+		return
+	}
+	ws.SetProgressReporter(newSSEReporter(job.events))
+
+	if err := ws.ScrapeAll(); err != nil {
+		m.finish(job, err)
+		return
+	}
+
+	if err := ws.Generate(cfg.Output.OutputPath); err != nil {
+		m.finish(job, err)
+		return
+	}
+
+	m.finish(job, nil)
+}
+
+func (m *jobManager) finish(job *Job, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err != nil {
+		job.Status = "failed"
+		job.Error = err.Error()
+	} else {
+		job.Status = "done"
+	}
+}
+
+// status snapshots job's exported fields while m.mu is held, so callers can
+// safely JSON-encode the result without racing finish().
+func (m *jobManager) status(job *Job) JobStatus {
+	return JobStatus{ID: job.ID, Title: job.Title, Status: job.Status, Error: job.Error}
+}
+
+// List returns a snapshot of every job started this process, most recent
+// last, for the library/dashboard page and the JSON API.
+func (m *jobManager) List() []JobStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statuses := make([]JobStatus, 0, len(m.jobs))
+	for i := 1; i <= m.next; i++ {
+		if job, ok := m.jobs[strconv.Itoa(i)]; ok {
+			statuses = append(statuses, m.status(job))
+		}
+	}
+	return statuses
+}
+
+// Get looks up a job by ID, for handleJobEvents to read its events channel.
+// The channel field is set once at creation and never mutated, so reading it
+// off the live *Job is race-free; Status/Error are not - use Status instead.
+func (m *jobManager) Get(id string) (*Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	return job, ok
+}
+
+// Status looks up a job by ID and returns a race-free snapshot suitable for
+// JSON encoding.
+func (m *jobManager) Status(id string) (JobStatus, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return JobStatus{}, false
+	}
+	return m.status(job), true
+}
+
+// newScraperFor builds a WebScraper for cfg with the same rule pack and
+// post-processing pipeline merging the CLI applies via --rules-dir/--scrapers-dir.
+func newScraperFor(cfg *config.Config, rulesDir, scrapersDir string) (*scraper.WebScraper, error) {
+	if cfg.Scraping.StartURL != "" {
+		rules, err := config.LoadRulesDir(rulesDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load rules directory: %w", err)
+		}
+		rule, err := config.MatchRule(rules, cfg.Scraping.StartURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to match rule: %w", err)
+		}
+		if rule != nil {
+			config.MergeRule(cfg, &config.Config{}, rule)
+		}
+	}
+
+	if err := config.ValidateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	ws, err := scraper.NewWebScraper(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scraper: %w", err)
+	}
+
+	scraperRules, err := pipeline.LoadRulesDir(scrapersDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load scrapers directory: %w", err)
+	}
+	ws.SetPipelineRules(scraperRules)
+
+	return ws, nil
+}
+
+// sseEvent is the wire format pushed over /api/jobs/events, one JSON object
+// per scrape lifecycle event (see scraper.ProgressReporter).
+type sseEvent struct {
+	Event string               `json:"event"`
+	Index int                  `json:"index,omitempty"`
+	Total int                  `json:"total,omitempty"`
+	URL   string               `json:"url,omitempty"`
+	Bytes int                  `json:"bytes,omitempty"`
+	Error string               `json:"error,omitempty"`
+	Step  string               `json:"step,omitempty"`
+	Stats *scraper.ScrapeStats `json:"stats,omitempty"`
+}
+
+// sseReporter adapts scraper.ProgressReporter onto a channel of sseEvent,
+// read by handleJobEvents and forwarded to the browser as SSE frames.
+type sseReporter struct {
+	events chan<- interface{}
+}
+
+func newSSEReporter(events chan<- interface{}) *sseReporter {
+	return &sseReporter{events: events}
+}
+
+func (r *sseReporter) emit(e sseEvent) {
+	select {
+	case r.events <- e:
+	default:
+		// Drop the event rather than block the scrape if a client isn't
+		// draining fast enough; GET /api/jobs still reflects final status.
+	}
+}
+
+func (r *sseReporter) OnDiscovered(total int) {
+	r.emit(sseEvent{Event: "discovered", Total: total})
+}
+
+func (r *sseReporter) OnChapterStart(idx int, url string) {
+	r.emit(sseEvent{Event: "chapter_start", Index: idx, URL: url})
+}
+
+func (r *sseReporter) OnChapterDone(idx int, bytes int, err error) {
+	e := sseEvent{Event: "chapter_done", Index: idx, Bytes: bytes}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	r.emit(e)
+}
+
+func (r *sseReporter) OnFinished(stats scraper.ScrapeStats) {
+	r.emit(sseEvent{Event: "finished", Stats: &stats})
+}
+
+func (r *sseReporter) OnPackagingStart(total int) {
+	r.emit(sseEvent{Event: "packaging_start", Total: total})
+}
+
+func (r *sseReporter) OnPackagingStep(name string) {
+	r.emit(sseEvent{Event: "packaging_step", Step: name})
+}
+
+func (r *sseReporter) OnPackagingDone() {
+	r.emit(sseEvent{Event: "packaging_done"})
+}
@@ -0,0 +1,268 @@
+// Package server implements the optional --serve HTTP UI: a small
+// self-hosted front end for validating configs, test-extracting a single
+// URL, running scrapes with live progress over Server-Sent Events, and
+// browsing previously generated output files. It's built entirely on the
+// standard library's net/http plus an embedded template set, so running
+// it doesn't pull in a web framework.
+package server
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"web-to-epub-go/internal/config"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.html"))
+
+// Server holds the state backing the HTTP UI: where generated output files
+// live, and the in-flight/completed scrape jobs started from it.
+type Server struct {
+	rulesDir    string
+	scrapersDir string
+	// outputPath is the only directory /library and /download serve files
+	// from - it is fixed at startup, never taken from a request, so a
+	// client can't point either handler outside it.
+	outputPath string
+
+	jobs *jobManager
+}
+
+// New creates a Server. rulesDir and scrapersDir are passed through to every
+// job the same way --rules-dir/--scrapers-dir are on the CLI; outputPath is
+// the directory /library lists and /download serves from.
+func New(rulesDir, scrapersDir, outputPath string) *Server {
+	return &Server{
+		rulesDir:    rulesDir,
+		scrapersDir: scrapersDir,
+		outputPath:  outputPath,
+		jobs:        newJobManager(),
+	}
+}
+
+// Handler returns the HTTP handler serving every route.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleIndex)
+	mux.HandleFunc("/library", s.handleLibrary)
+	mux.HandleFunc("/download/", s.handleDownload)
+	mux.HandleFunc("/api/config/validate", s.handleValidateConfig)
+	mux.HandleFunc("/api/test", s.handleTest)
+	mux.HandleFunc("/api/jobs", s.handleJobs)
+	mux.HandleFunc("/api/jobs/events", s.handleJobEvents)
+	return mux
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	s.render(w, "index.html", nil)
+}
+
+func (s *Server) handleLibrary(w http.ResponseWriter, r *http.Request) {
+	entries, err := os.ReadDir(s.outputPath)
+	if err != nil && !os.IsNotExist(err) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var books []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".epub", ".pdf":
+			books = append(books, e.Name())
+		}
+	}
+
+	s.render(w, "library.html", struct {
+		Dir   string
+		Books []string
+	}{Dir: s.outputPath, Books: books})
+}
+
+// handleDownload serves a single file by name out of s.outputPath. name is
+// reduced to its base (filepath.Base strips any ".." or directory
+// components), and the directory itself always comes from server
+// configuration, never the request, so this can't be used to read files
+// outside s.outputPath.
+func (s *Server) handleDownload(w http.ResponseWriter, r *http.Request) {
+	name := filepath.Base(strings.TrimPrefix(r.URL.Path, "/download/"))
+	http.ServeFile(w, r, filepath.Join(s.outputPath, name))
+}
+
+func (s *Server) render(w http.ResponseWriter, name string, data interface{}) {
+	if err := templates.ExecuteTemplate(w, name, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// parseConfig reads a YAML or JSON config submitted as a request body,
+// mirroring config.LoadConfig's format sniffing for a file on disk.
+func parseConfig(body io.Reader) (*config.Config, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	cfg := config.DefaultConfig()
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config (tried YAML and JSON): %w", err)
+		}
+	}
+	return cfg, nil
+}
+
+type validateResponse struct {
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+func (s *Server) handleValidateConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	cfg, err := parseConfig(r.Body)
+	if err != nil {
+		writeJSON(w, validateResponse{Valid: false, Error: err.Error()})
+		return
+	}
+
+	if err := config.ValidateConfig(cfg); err != nil {
+		writeJSON(w, validateResponse{Valid: false, Error: err.Error()})
+		return
+	}
+
+	writeJSON(w, validateResponse{Valid: true})
+}
+
+type testRequest struct {
+	Config string `json:"config"`
+	URL    string `json:"url"`
+}
+
+type testResponse struct {
+	Title    string            `json:"title"`
+	Content  string            `json:"content"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+func (s *Server) handleTest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req testRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, testResponse{Error: err.Error()})
+		return
+	}
+
+	cfg, err := parseConfig(strings.NewReader(req.Config))
+	if err != nil {
+		writeJSON(w, testResponse{Error: err.Error()})
+		return
+	}
+	cfg.Scraping.StartURL = req.URL
+
+	ws, err := newScraperFor(cfg, s.rulesDir, s.scrapersDir)
+	if err != nil {
+		writeJSON(w, testResponse{Error: err.Error()})
+		return
+	}
+
+	chapter, _, err := ws.ScrapeTest(req.URL)
+	if err != nil {
+		writeJSON(w, testResponse{Error: err.Error()})
+		return
+	}
+
+	writeJSON(w, testResponse{Title: chapter.Title, Content: chapter.Content, Metadata: chapter.Metadata})
+}
+
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, s.jobs.List())
+	case http.MethodPost:
+		var req testRequest // reuses the {config, url} shape; url is unused here
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		cfg, err := parseConfig(strings.NewReader(req.Config))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		job := s.jobs.Start(cfg, s.rulesDir, s.scrapersDir)
+		writeJSON(w, job)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleJobEvents streams a single job's progress events as Server-Sent
+// Events until the job finishes or the client disconnects.
+func (s *Server) handleJobEvents(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	job, ok := s.jobs.Get(id)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	for {
+		select {
+		case event, open := <-job.events:
+			if !open {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"web-to-epub-go/internal/config"
+)
+
+// globalGreyDriver lists https://www.globalgreyebooks.com, whose catalog is
+// paginated as all-ebooks.html, all-ebooks-2.html, ... rather than a query
+// parameter. Like Standard
+// Ebooks, every title has a direct EPUB download.
+type globalGreyDriver struct {
+	baseURL string
+	client  *http.Client
+}
+
+func init() {
+	Register("globalgrey", &globalGreyDriver{
+		baseURL: "https://www.globalgreyebooks.com",
+		client:  &http.Client{},
+	})
+}
+
+func (d *globalGreyDriver) List(ctx context.Context) ([]Title, error) {
+	var titles []Title
+
+	for page := 1; ; page++ {
+		pageURL := d.baseURL + "/all-ebooks.html"
+		if page > 1 {
+			pageURL = fmt.Sprintf("%s/all-ebooks-%d.html", d.baseURL, page)
+		}
+
+		doc, err := fetchDoc(ctx, d.client, pageURL)
+		if err != nil {
+			if page == 1 {
+				return nil, fmt.Errorf("globalgrey: failed to fetch catalog page %d: %w", page, err)
+			}
+			break
+		}
+
+		found := 0
+		doc.Find(".book-list a.book-title").Each(func(_ int, a *goquery.Selection) {
+			href, ok := a.Attr("href")
+			if !ok {
+				return
+			}
+			found++
+			titles = append(titles, Title{
+				Name:    strings.TrimSpace(a.Text()),
+				URL:     d.baseURL + href,
+				EPUBURL: strings.TrimSuffix(d.baseURL+href, ".html") + "-epub.epub",
+			})
+		})
+		if found == 0 {
+			break
+		}
+	}
+
+	return titles, nil
+}
+
+func (d *globalGreyDriver) Resolve(ctx context.Context, query string) (*Title, error) {
+	titles, err := d.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query = strings.ToLower(query)
+	for i := range titles {
+		if strings.Contains(strings.ToLower(titles[i].Name), query) {
+			return &titles[i], nil
+		}
+	}
+	return nil, errNotFound("globalgrey", query)
+}
+
+func (d *globalGreyDriver) BuildConfig(title *Title) (*config.Config, error) {
+	cfg := config.DefaultConfig()
+	cfg.Book.Title = title.Name
+	cfg.Scraping.StartURL = title.URL
+	cfg.Navigation.Method = "toc"
+	cfg.Navigation.TOCUrl = title.URL
+	cfg.Navigation.TOCLinkSelector = "#chapters a"
+	cfg.ContentDetection.Strategy = "readability"
+	return cfg, nil
+}
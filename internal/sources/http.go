@@ -0,0 +1,57 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// userAgent identifies web-to-epub to catalog sites when crawling them for
+// source listings, independent of the scraping config's own UserAgent since
+// this happens before a config exists.
+const userAgent = "Mozilla/5.0 (compatible; WebToEPUB/1.0)"
+
+// fetchDoc fetches rawURL and parses it as HTML.
+func fetchDoc(ctx context.Context, client *http.Client, rawURL string) (*goquery.Document, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d fetching %s", resp.StatusCode, rawURL)
+	}
+
+	return goquery.NewDocumentFromReader(resp.Body)
+}
+
+// fetchBytes fetches rawURL's raw body.
+func fetchBytes(ctx context.Context, client *http.Client, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d fetching %s", resp.StatusCode, rawURL)
+	}
+
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,64 @@
+// Package sources provides built-in integrations with well-known ebook
+// catalogs (Standard Ebooks, Project Gutenberg, Global Grey, ...), so a user
+// can scrape a title by name instead of hand-writing a site-specific config.
+package sources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"web-to-epub-go/internal/config"
+)
+
+// Title describes one catalog entry resolved by a Driver.
+type Title struct {
+	Name     string
+	Author   string
+	URL      string // catalog detail page
+	EPUBURL  string // direct download for a pre-built EPUB, if the source offers one
+	Subjects []string
+}
+
+// Driver lets a catalog plug into --source. Implementations must be safe for
+// concurrent use.
+type Driver interface {
+	// List returns every title currently offered by the catalog.
+	List(ctx context.Context) ([]Title, error)
+	// Resolve finds the catalog entry matching query, a case-insensitive
+	// substring match against title; the first match wins and the author is
+	// not compared.
+	Resolve(ctx context.Context, query string) (*Title, error)
+	// BuildConfig produces a full chapter-scrape config for title. It does
+	// not download a pre-built EPUB (EPUBURL); that is left to the caller.
+	BuildConfig(title *Title) (*config.Config, error)
+}
+
+var registry = map[string]Driver{}
+
+// Register adds a Driver under name, so third parties can add more catalogs
+// without touching this package.
+func Register(name string, d Driver) {
+	registry[name] = d
+}
+
+// Get returns the Driver registered under name, if any.
+func Get(name string) (Driver, bool) {
+	d, ok := registry[name]
+	return d, ok
+}
+
+// Names returns every registered source name, sorted.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// errNotFound is returned by Resolve when no title matches query.
+func errNotFound(source, query string) error {
+	return fmt.Errorf("%s: no title matching %q", source, query)
+}
@@ -0,0 +1,110 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"web-to-epub-go/internal/config"
+)
+
+// standardEbooksDriver lists https://standardebooks.org/ebooks, a catalog
+// paginated as ebooks?page=N, stopping once the "next" link is absent or
+// disabled. Every title has a predictable direct EPUB download URL, so
+// Resolve never needs to fall back to chapter scraping.
+type standardEbooksDriver struct {
+	baseURL string
+	client  *http.Client
+}
+
+func init() {
+	Register("standard-ebooks", &standardEbooksDriver{
+		baseURL: "https://standardebooks.org",
+		client:  &http.Client{},
+	})
+}
+
+func (d *standardEbooksDriver) List(ctx context.Context) ([]Title, error) {
+	var titles []Title
+
+	for page := 1; ; page++ {
+		doc, err := fetchDoc(ctx, d.client, fmt.Sprintf("%s/ebooks?page=%d", d.baseURL, page))
+		if err != nil {
+			return nil, fmt.Errorf("standard-ebooks: failed to fetch catalog page %d: %w", page, err)
+		}
+
+		found := 0
+		doc.Find("ol.ebooks-list li a").Each(func(_ int, a *goquery.Selection) {
+			href, ok := a.Attr("href")
+			if !ok {
+				return
+			}
+			found++
+			name, author := splitEbookListEntry(a.Text())
+			titles = append(titles, Title{
+				Name:    name,
+				Author:  author,
+				URL:     d.baseURL + href,
+				EPUBURL: d.baseURL + href + "/downloads/" + ebookSlug(href) + ".epub",
+			})
+		})
+		if found == 0 {
+			break
+		}
+
+		next := doc.Find("a[rel=next]").Not("[aria-disabled=true], .disabled")
+		if next.Length() == 0 {
+			break
+		}
+	}
+
+	return titles, nil
+}
+
+func (d *standardEbooksDriver) Resolve(ctx context.Context, query string) (*Title, error) {
+	titles, err := d.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query = strings.ToLower(query)
+	for i := range titles {
+		if strings.Contains(strings.ToLower(titles[i].Name), query) {
+			return &titles[i], nil
+		}
+	}
+	return nil, errNotFound("standard-ebooks", query)
+}
+
+func (d *standardEbooksDriver) BuildConfig(title *Title) (*config.Config, error) {
+	cfg := config.DefaultConfig()
+	cfg.Book.Title = title.Name
+	cfg.Book.Author = title.Author
+	cfg.Scraping.StartURL = title.URL
+	cfg.Navigation.Method = "toc"
+	cfg.Navigation.TOCUrl = title.URL + "/text/single-page"
+	cfg.Navigation.TOCLinkSelector = "#filtered"
+	cfg.ContentDetection.Strategy = "css_selector"
+	cfg.ContentDetection.CSSSelector = "main"
+	return cfg, nil
+}
+
+// splitEbookListEntry splits a Standard Ebooks listing link's text, formatted
+// "Title, by Author", into its two parts.
+func splitEbookListEntry(text string) (name, author string) {
+	text = strings.TrimSpace(text)
+	if idx := strings.LastIndex(text, ", by "); idx != -1 {
+		return text[:idx], text[idx+len(", by "):]
+	}
+	return text, ""
+}
+
+// ebookSlug extracts the trailing "/ebooks/<author>/<title>" path and turns
+// it into the "<author>_<title>" slug Standard Ebooks uses for its download
+// filenames.
+func ebookSlug(href string) string {
+	trimmed := strings.TrimPrefix(href, "/ebooks/")
+	return strings.ReplaceAll(trimmed, "/", "_")
+}